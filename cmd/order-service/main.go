@@ -2,18 +2,29 @@ package main
 
 import (
 	"context"
-	"github.com/go-playground/validator/v10"
+	"errors"
 	"github.com/gofiber/fiber/v2"
+	"github.com/sdvaanyaa/order-service/internal/cache"
 	"github.com/sdvaanyaa/order-service/internal/config"
 	"github.com/sdvaanyaa/order-service/internal/consumer"
 	"github.com/sdvaanyaa/order-service/internal/handler"
+	"github.com/sdvaanyaa/order-service/internal/outbox"
+	"github.com/sdvaanyaa/order-service/internal/repository"
 	"github.com/sdvaanyaa/order-service/internal/repository/postgres"
 	"github.com/sdvaanyaa/order-service/internal/service"
+	"github.com/sdvaanyaa/order-service/pkg/lifecycle"
 	"github.com/sdvaanyaa/order-service/pkg/pgdb"
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
+)
+
+const (
+	idempotencyCleanupInterval = time.Hour
+	backgroundStopTimeout      = 10 * time.Second
 )
 
 func main() {
@@ -29,22 +40,45 @@ func main() {
 		log.Error("db init failed", "err", err)
 		os.Exit(1)
 	}
-	defer db.Close()
 
 	transactor := pgdb.NewTransactor(db)
 	repo := postgres.New(db, log)
-	val := validator.New()
-	svc := service.New(repo, transactor, log, val)
-	h := handler.New(svc)
+	val := service.NewValidator()
+	orderCache := cache.NewLRU(cfg.Cache)
+	notFound := cache.NewNegative(cfg.Cache.NegativeTTL, cfg.Cache.NegativeMaxEntries)
+	outboxRepo := postgres.NewOutboxRepo(db, log)
+	svc := service.New(repo, outboxRepo, transactor, db, log, val, orderCache, notFound, cfg.Cache, cfg.Validation)
+	idempotency := postgres.NewIdempotencyRepo(db, log)
+	h := handler.New(svc, idempotency, cfg.HTTP)
 
 	cons, err := consumer.New(cfg.Kafka, svc, log)
 	if err != nil {
 		log.Error("kafka consumer init failed", "err", err)
 		os.Exit(1)
 	}
+	relay, err := outbox.New(cfg.Outbox, cfg.Kafka.Brokers, outboxRepo, log)
+	if err != nil {
+		log.Error("outbox relay init failed", "err", err)
+		os.Exit(1)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+
+	// jobs that use the database and must end before it is closed
+	var jobs sync.WaitGroup
+	background := func(job func(ctx context.Context)) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			job(ctx)
+		}()
+	}
+
+	background(svc.WarmCache)
+	background(svc.WatchChanges)
+	background(func(ctx context.Context) { cleanupIdempotencyKeys(ctx, idempotency, log) })
 	go cons.Run(ctx)
+	go relay.Run(ctx)
 	<-cons.Ready()
 	log.Info("kafka consumer ready")
 
@@ -62,7 +96,42 @@ func main() {
 	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
 	<-sig
 
-	if err = app.Shutdown(); err != nil {
+	log.Info("shutting down")
+
+	lc := lifecycle.New(log)
+	lc.Add("http server", cfg.HTTP.ShutdownTimeout, app.ShutdownWithContext)
+	lc.Add("kafka consumer", cfg.Kafka.DrainTimeout, cons.Stop)
+	lc.Add("outbox relay", cfg.Outbox.StopTimeout, relay.Stop)
+	lc.Add("background jobs", backgroundStopTimeout, func(ctx context.Context) error {
+		cancel()
+		return errors.Join(svc.Close(ctx), lifecycle.Wait(ctx, &jobs))
+	})
+	lc.Add("database", 0, func(context.Context) error {
+		db.Close()
+		return nil
+	})
+
+	if err = lc.Shutdown(context.Background()); err != nil {
 		log.Error("shutdown failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func cleanupIdempotencyKeys(ctx context.Context, repo repository.IdempotencyRepository, log *slog.Logger) {
+	ticker := time.NewTicker(idempotencyCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := repo.DeleteExpiredKeys(ctx)
+			if err != nil {
+				log.Error("idempotency keys cleanup failed", slog.Any("error", err))
+				continue
+			}
+			log.Info("expired idempotency keys deleted", slog.Int64("count", deleted))
+		}
 	}
 }
@@ -64,9 +64,27 @@ func main() {
 
 func generateRandomOrder() models.Order {
 	uid := uuid.NewString()
+	trackNumber := fmt.Sprintf("TRACK%d", rand.Intn(10000))
+
+	item := models.Item{
+		ChrtID:      int64(rand.Intn(100000)),
+		TrackNumber: trackNumber,
+		Price:       rand.Intn(500) + 50,
+		Rid:         "rid",
+		Name:        "Item Name",
+		Sale:        rand.Intn(50),
+		Size:        "M",
+		NmID:        int64(rand.Intn(100000)),
+		Brand:       "Brand",
+		Status:      200,
+	}
+	item.TotalPrice = item.DiscountedPrice()
+
+	const deliveryCost = 50
+
 	return models.Order{
 		OrderUID:    uid,
-		TrackNumber: fmt.Sprintf("TRACK%d", rand.Intn(10000)),
+		TrackNumber: trackNumber,
 		Entry:       "ENTRY",
 		Delivery: models.Delivery{
 			Name:    "Test User",
@@ -81,28 +99,14 @@ func generateRandomOrder() models.Order {
 			Transaction:  uid,
 			Currency:     "USD",
 			Provider:     "pay",
-			Amount:       rand.Intn(1000) + 100,
+			Amount:       item.TotalPrice + deliveryCost,
 			PaymentDt:    time.Now().Unix(),
 			Bank:         "bank",
-			DeliveryCost: 50,
-			GoodsTotal:   100,
+			DeliveryCost: deliveryCost,
+			GoodsTotal:   item.TotalPrice,
 			CustomFee:    0,
 		},
-		Items: []models.Item{
-			{
-				ChrtID:      int64(rand.Intn(100000)),
-				TrackNumber: fmt.Sprintf("ITEM%d", rand.Intn(10000)),
-				Price:       rand.Intn(500) + 50,
-				Rid:         "rid",
-				Name:        "Item Name",
-				Sale:        rand.Intn(50),
-				Size:        "M",
-				TotalPrice:  100,
-				NmID:        int64(rand.Intn(100000)),
-				Brand:       "Brand",
-				Status:      200,
-			},
-		},
+		Items:             []models.Item{item},
 		Locale:            "en",
 		InternalSignature: "",
 		CustomerID:        "cust",
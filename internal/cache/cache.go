@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"github.com/sdvaanyaa/order-service/internal/models"
+)
+
+type Cache interface {
+	Get(uid string) (*models.Order, bool)
+	Set(order *models.Order)
+	// BeginWarm and EndWarm bracket a warmup. In between, uids that are set,
+	// deleted or purged are remembered, so Warm does not bring back an older
+	// copy of them.
+	BeginWarm()
+	EndWarm()
+	// Generation returns a counter that grows with every invalidation during
+	// a warmup. It is taken before a page is read and passed to Warm.
+	Generation() uint64
+	// Warm adds an order only if it is not cached yet and was not invalidated
+	// after gen, without displacing entries that are in use. It reports
+	// whether the order was added.
+	Warm(order *models.Order, gen uint64) bool
+	// Peek returns a cached order without touching its recency or the stats.
+	Peek(uid string) (*models.Order, bool)
+	Delete(uid string)
+	Purge()
+	Len() int
+	Stats() Stats
+}
+
+type Stats struct {
+	Entries     int    `json:"entries"`
+	Bytes       int64  `json:"bytes"`
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	Expirations uint64 `json:"expirations"`
+}
@@ -0,0 +1,328 @@
+package cache
+
+import (
+	"container/list"
+	"github.com/sdvaanyaa/order-service/internal/config"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultShards = 16
+
+	// rough per-entry overhead of the list element, map slot and struct
+	orderOverhead = 512
+	itemOverhead  = 128
+)
+
+// LRU is a bounded order cache split into independently locked shards.
+// Each shard evicts its least recently used entries once it exceeds its part
+// of the entry or byte budget. Entries older than ttl are dropped on access.
+type LRU struct {
+	shards []*shard
+	ttl    time.Duration
+	now    func() time.Time
+
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+
+	// invalidation generation, see Generation
+	gen      atomic.Uint64
+	purgedAt atomic.Uint64
+	warming  atomic.Int32
+}
+
+type shard struct {
+	mu         sync.Mutex
+	items      map[string]*list.Element
+	order      *list.List
+	bytes      int64
+	maxEntries int
+	maxBytes   int64
+
+	// uids invalidated while a warmup runs, with the generation they were
+	// invalidated at
+	tombstones map[string]uint64
+}
+
+type entry struct {
+	order     *models.Order
+	size      int64
+	expiresAt time.Time
+}
+
+func NewLRU(cfg config.CacheConfig) *LRU {
+	shards := cfg.Shards
+	if shards <= 0 {
+		shards = DefaultShards
+	}
+
+	c := &LRU{
+		shards: make([]*shard, shards),
+		ttl:    cfg.TTL,
+		now:    time.Now,
+	}
+
+	for i := range c.shards {
+		c.shards[i] = &shard{
+			items:      make(map[string]*list.Element),
+			order:      list.New(),
+			tombstones: make(map[string]uint64),
+			maxEntries: perShard(cfg.MaxEntries, shards),
+			maxBytes:   int64(perShard(int(cfg.MaxBytes), shards)),
+		}
+	}
+
+	return c
+}
+
+func perShard(total, shards int) int {
+	if total <= 0 {
+		return 0
+	}
+	return (total + shards - 1) / shards
+}
+
+func (c *LRU) shardFor(uid string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(uid))
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+func (c *LRU) Get(uid string) (*models.Order, bool) {
+	s := c.shardFor(uid)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[uid]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	e := el.Value.(*entry) //nolint:errcheck // list only holds *entry
+	if !e.expiresAt.IsZero() && c.now().After(e.expiresAt) {
+		s.remove(el)
+		c.expirations.Add(1)
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	s.order.MoveToFront(el)
+	c.hits.Add(1)
+
+	return e.order, true
+}
+
+func (c *LRU) newEntry(order *models.Order) *entry {
+	e := &entry{
+		order: order,
+		size:  EstimateSize(order),
+	}
+	if c.ttl > 0 {
+		e.expiresAt = c.now().Add(c.ttl)
+	}
+	return e
+}
+
+func (c *LRU) Set(order *models.Order) {
+	e := c.newEntry(order)
+	s := c.shardFor(order.OrderUID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[order.OrderUID]; ok {
+		s.remove(el)
+	}
+	c.tombstone(s, order.OrderUID)
+
+	s.items[order.OrderUID] = s.order.PushFront(e)
+	s.bytes += e.size
+
+	c.evictions.Add(s.evict())
+}
+
+// BeginWarm starts recording invalidations for Warm. Every BeginWarm must be
+// followed by EndWarm.
+func (c *LRU) BeginWarm() {
+	c.warming.Add(1)
+}
+
+// EndWarm stops recording invalidations once no warmup is left running.
+func (c *LRU) EndWarm() {
+	if c.warming.Add(-1) > 0 {
+		return
+	}
+
+	for _, s := range c.shards {
+		s.mu.Lock()
+		clear(s.tombstones)
+		s.mu.Unlock()
+	}
+}
+
+// Generation returns the current invalidation generation. A warmup takes it
+// before reading a page and passes it to Warm for the orders of that page.
+func (c *LRU) Generation() uint64 {
+	return c.gen.Load()
+}
+
+// Warm inserts the order as the least recently used entry, so a bulk load
+// never evicts anything. The order is skipped if it is cached already, if
+// it was set, deleted or purged after gen, since the page it came from may
+// predate that change, or if it does not fit the shard budget.
+func (c *LRU) Warm(order *models.Order, gen uint64) bool {
+	e := c.newEntry(order)
+	s := c.shardFor(order.OrderUID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[order.OrderUID]; ok {
+		return false
+	}
+
+	if c.purgedAt.Load() > gen || s.tombstones[order.OrderUID] > gen {
+		return false
+	}
+
+	s.items[order.OrderUID] = s.order.PushBack(e)
+	s.bytes += e.size
+
+	if s.overBudget() {
+		s.remove(s.order.Back())
+		return false
+	}
+
+	return true
+}
+
+func (c *LRU) Delete(uid string) {
+	s := c.shardFor(uid)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[uid]; ok {
+		s.remove(el)
+	}
+	c.tombstone(s, uid)
+}
+
+func (c *LRU) Peek(uid string) (*models.Order, bool) {
+	s := c.shardFor(uid)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[uid]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry) //nolint:errcheck // list only holds *entry
+	if !e.expiresAt.IsZero() && c.now().After(e.expiresAt) {
+		return nil, false
+	}
+
+	return e.order, true
+}
+
+// Purge drops every entry. The hit and miss counters are kept.
+func (c *LRU) Purge() {
+	c.purgedAt.Store(c.gen.Add(1))
+
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.items = make(map[string]*list.Element)
+		s.order.Init()
+		s.bytes = 0
+		s.mu.Unlock()
+	}
+}
+
+func (c *LRU) Len() int {
+	total := 0
+	for _, s := range c.shards {
+		s.mu.Lock()
+		total += len(s.items)
+		s.mu.Unlock()
+	}
+	return total
+}
+
+func (c *LRU) Stats() Stats {
+	stats := Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+	}
+
+	for _, s := range c.shards {
+		s.mu.Lock()
+		stats.Entries += len(s.items)
+		stats.Bytes += s.bytes
+		s.mu.Unlock()
+	}
+
+	return stats
+}
+
+// tombstone records that uid was invalidated, so a running warmup does not
+// bring back an order read before. The shard must be locked.
+func (c *LRU) tombstone(s *shard, uid string) {
+	if c.warming.Load() > 0 {
+		s.tombstones[uid] = c.gen.Add(1)
+	}
+}
+
+// evict drops least recently used entries until the shard fits its budget
+// and returns how many were dropped. The newest entry is always kept.
+func (s *shard) evict() uint64 {
+	var evicted uint64
+
+	for s.order.Len() > 1 && s.overBudget() {
+		s.remove(s.order.Back())
+		evicted++
+	}
+
+	return evicted
+}
+
+func (s *shard) overBudget() bool {
+	return (s.maxEntries > 0 && s.order.Len() > s.maxEntries) ||
+		(s.maxBytes > 0 && s.bytes > s.maxBytes)
+}
+
+func (s *shard) remove(el *list.Element) {
+	e := s.order.Remove(el).(*entry) //nolint:errcheck // list only holds *entry
+	delete(s.items, e.order.OrderUID)
+	s.bytes -= e.size
+}
+
+// EstimateSize approximates the memory held by an order.
+func EstimateSize(order *models.Order) int64 {
+	size := orderOverhead + len(order.OrderUID) + len(order.TrackNumber) + len(order.Entry) +
+		len(order.Locale) + len(order.InternalSignature) + len(order.CustomerID) +
+		len(order.DeliveryService) + len(order.Shardkey) + len(order.OofShard)
+
+	d := order.Delivery
+	size += len(d.Name) + len(d.Phone) + len(d.Zip) + len(d.City) + len(d.Address) + len(d.Region) + len(d.Email)
+
+	p := order.Payment
+	size += len(p.Transaction) + len(p.RequestID) + len(p.Currency) + len(p.Provider) + len(p.Bank)
+
+	for _, item := range order.Items {
+		size += itemOverhead + len(item.TrackNumber) + len(item.Rid) + len(item.Name) + len(item.Size) + len(item.Brand)
+	}
+
+	return int64(size)
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/sdvaanyaa/order-service/internal/config"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRU(config.CacheConfig{MaxEntries: 2, Shards: 1})
+
+	c.Set(&models.Order{OrderUID: "uid1"})
+	c.Set(&models.Order{OrderUID: "uid2"})
+	_, _ = c.Get("uid1")
+	c.Set(&models.Order{OrderUID: "uid3"})
+
+	_, ok := c.Get("uid2")
+	assert.False(t, ok)
+	_, ok = c.Get("uid1")
+	assert.True(t, ok)
+	_, ok = c.Get("uid3")
+	assert.True(t, ok)
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestLRU_MaxBytes(t *testing.T) {
+	t.Parallel()
+
+	order := &models.Order{OrderUID: "uid1"}
+	size := EstimateSize(order)
+
+	c := NewLRU(config.CacheConfig{MaxBytes: 2 * size, Shards: 1})
+	for i := range 5 {
+		c.Set(&models.Order{OrderUID: fmt.Sprintf("uid%d", i)})
+	}
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, 2*size, stats.Bytes)
+	assert.Equal(t, uint64(3), stats.Evictions)
+}
+
+func TestLRU_Warm(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRU(config.CacheConfig{MaxEntries: 2, Shards: 1})
+
+	fresh := &models.Order{OrderUID: "uid1", Version: 2}
+	c.Set(fresh)
+
+	gen := c.Generation()
+	assert.False(t, c.Warm(&models.Order{OrderUID: "uid1", Version: 1}, gen))
+	assert.True(t, c.Warm(&models.Order{OrderUID: "uid2"}, gen))
+	assert.False(t, c.Warm(&models.Order{OrderUID: "uid3"}, gen))
+
+	got, ok := c.Get("uid1")
+	assert.True(t, ok)
+	assert.Same(t, fresh, got)
+	_, ok = c.Get("uid2")
+	assert.True(t, ok)
+	_, ok = c.Get("uid3")
+	assert.False(t, ok)
+	assert.Equal(t, uint64(0), c.Stats().Evictions)
+}
+
+func TestLRU_Warm_SkipsInvalidated(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRU(config.CacheConfig{Shards: 1})
+	c.Set(&models.Order{OrderUID: "uid1", Version: 1})
+
+	c.BeginWarm()
+	gen := c.Generation()
+
+	c.Delete("uid1")
+	c.Delete("uid2")
+	assert.False(t, c.Warm(&models.Order{OrderUID: "uid1", Version: 1}, gen), "deleted after the page was read")
+	assert.False(t, c.Warm(&models.Order{OrderUID: "uid2"}, gen), "invalidated while not cached")
+	assert.True(t, c.Warm(&models.Order{OrderUID: "uid2"}, c.Generation()), "read after the invalidation")
+
+	c.Purge()
+	assert.False(t, c.Warm(&models.Order{OrderUID: "uid3"}, gen), "purged after the page was read")
+	assert.True(t, c.Warm(&models.Order{OrderUID: "uid3"}, c.Generation()))
+
+	c.EndWarm()
+	gen = c.Generation()
+	c.Delete("uid4")
+	assert.True(t, c.Warm(&models.Order{OrderUID: "uid4"}, gen), "nothing recorded without a warmup")
+}
+
+func TestLRU_TTL(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	c := NewLRU(config.CacheConfig{TTL: time.Minute, Shards: 1})
+	c.now = func() time.Time { return now }
+
+	c.Set(&models.Order{OrderUID: "uid1"})
+	_, ok := c.Get("uid1")
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.Get("uid1")
+	assert.False(t, ok)
+
+	stats := c.Stats()
+	assert.Equal(t, 0, stats.Entries)
+	assert.Equal(t, uint64(1), stats.Expirations)
+}
+
+func TestLRU_OverwriteAndDelete(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRU(config.CacheConfig{MaxEntries: 10})
+
+	c.Set(&models.Order{OrderUID: "uid1", TrackNumber: "old"})
+	c.Set(&models.Order{OrderUID: "uid1", TrackNumber: "new"})
+
+	got, ok := c.Get("uid1")
+	assert.True(t, ok)
+	assert.Equal(t, "new", got.TrackNumber)
+	assert.Equal(t, 1, c.Len())
+
+	c.Delete("uid1")
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, int64(0), c.Stats().Bytes)
+}
+
+func TestLRU_PeekAndPurge(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRU(config.CacheConfig{MaxEntries: 10})
+
+	c.Set(&models.Order{OrderUID: "uid1"})
+	c.Set(&models.Order{OrderUID: "uid2"})
+
+	_, ok := c.Peek("uid1")
+	assert.True(t, ok)
+	_, ok = c.Peek("uid3")
+	assert.False(t, ok)
+	assert.Equal(t, uint64(0), c.Stats().Hits)
+	assert.Equal(t, uint64(0), c.Stats().Misses)
+
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, int64(0), c.Stats().Bytes)
+}
+
+func TestLRU_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRU(config.CacheConfig{MaxEntries: 100})
+
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				uid := fmt.Sprintf("uid%d", (w*500+i)%300)
+				c.Set(&models.Order{OrderUID: uid})
+				_, _ = c.Get(uid)
+				if i%10 == 0 {
+					c.Delete(uid)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, c.Len(), 100+DefaultShards)
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Negative remembers uids recently reported as missing, so repeated lookups
+// of unknown orders don't reach the database until ttl passes. A nil
+// *Negative is a valid, always empty cache.
+type Negative struct {
+	mu         sync.Mutex
+	entries    map[string]time.Time
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+}
+
+func NewNegative(ttl time.Duration, maxEntries int) *Negative {
+	return &Negative{
+		entries:    make(map[string]time.Time),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+func (n *Negative) Add(uid string) {
+	if n == nil || n.ttl <= 0 {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := n.now()
+	if n.maxEntries > 0 && len(n.entries) >= n.maxEntries {
+		n.purgeExpired(now)
+		if len(n.entries) >= n.maxEntries {
+			clear(n.entries)
+		}
+	}
+
+	n.entries[uid] = now.Add(n.ttl)
+}
+
+func (n *Negative) Has(uid string) bool {
+	if n == nil {
+		return false
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	expiresAt, ok := n.entries[uid]
+	if !ok {
+		return false
+	}
+
+	if n.now().After(expiresAt) {
+		delete(n.entries, uid)
+		return false
+	}
+
+	return true
+}
+
+func (n *Negative) Delete(uid string) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	delete(n.entries, uid)
+	n.mu.Unlock()
+}
+
+func (n *Negative) Purge() {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	clear(n.entries)
+	n.mu.Unlock()
+}
+
+func (n *Negative) purgeExpired(now time.Time) {
+	for uid, expiresAt := range n.entries {
+		if now.After(expiresAt) {
+			delete(n.entries, uid)
+		}
+	}
+}
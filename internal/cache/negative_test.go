@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNegative(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	n := NewNegative(time.Second, 2)
+	n.now = func() time.Time { return now }
+
+	n.Add("uid1")
+	assert.True(t, n.Has("uid1"))
+	assert.False(t, n.Has("uid2"))
+
+	now = now.Add(2 * time.Second)
+	assert.False(t, n.Has("uid1"))
+
+	n.Add("uid1")
+	n.Delete("uid1")
+	assert.False(t, n.Has("uid1"))
+
+	n.Add("uid1")
+	n.Add("uid2")
+	n.Add("uid3")
+	assert.True(t, n.Has("uid3"))
+	assert.LessOrEqual(t, len(n.entries), 2)
+
+	n.Purge()
+	assert.False(t, n.Has("uid3"))
+
+	var disabled *Negative
+	disabled.Add("uid1")
+	disabled.Purge()
+	assert.False(t, disabled.Has("uid1"))
+}
@@ -4,13 +4,18 @@ import (
 	"fmt"
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
+	"github.com/sdvaanyaa/order-service/pkg/retry"
 	"log/slog"
+	"time"
 )
 
 type Config struct {
-	Postgres PostgresConfig
-	HTTP     HTTPConfig
-	Kafka    KafkaConfig
+	Postgres   PostgresConfig
+	HTTP       HTTPConfig
+	Kafka      KafkaConfig
+	Cache      CacheConfig
+	Validation ValidationConfig
+	Outbox     OutboxConfig
 }
 
 type PostgresConfig struct {
@@ -30,7 +35,11 @@ func (c PostgresConfig) DSN() string {
 }
 
 type HTTPConfig struct {
-	Port string `env:"HTTP_PORT" envDefault:"8080"`
+	Port           string        `env:"HTTP_PORT" envDefault:"8080"`
+	AdminToken     string        `env:"HTTP_ADMIN_TOKEN"`
+	IdempotencyTTL time.Duration `env:"HTTP_IDEMPOTENCY_TTL" envDefault:"24h"`
+	// ShutdownTimeout bounds how long open requests may take on shutdown.
+	ShutdownTimeout time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" envDefault:"10s"`
 }
 
 func (c HTTPConfig) Address() string {
@@ -41,6 +50,106 @@ type KafkaConfig struct {
 	Brokers []string `env:"KAFKA_BROKERS" envSeparator:"," envDefault:"localhost:9092"`
 	Topic   string   `env:"KAFKA_TOPIC" envDefault:"orders"`
 	Group   string   `env:"KAFKA_GROUP" envDefault:"order-service"`
+	// DLQTopic receives messages that could not be processed while
+	// DLQEnabled is set. Otherwise such messages are logged and dropped.
+	DLQEnabled bool   `env:"KAFKA_DLQ_ENABLED" envDefault:"true"`
+	DLQTopic   string `env:"KAFKA_DLQ_TOPIC" envDefault:"orders-dlq"`
+
+	RetryMaxAttempts int           `env:"KAFKA_RETRY_MAX_ATTEMPTS" envDefault:"5"`
+	RetryBaseDelay   time.Duration `env:"KAFKA_RETRY_BASE_DELAY" envDefault:"1s"`
+	RetryMaxDelay    time.Duration `env:"KAFKA_RETRY_MAX_DELAY" envDefault:"30s"`
+	// RetryJitter is one of full, equal or decorrelated.
+	RetryJitter    string        `env:"KAFKA_RETRY_JITTER" envDefault:"full"`
+	AttemptTimeout time.Duration `env:"KAFKA_ATTEMPT_TIMEOUT" envDefault:"10s"`
+
+	// Workers process messages of one partition concurrently, MaxInFlight
+	// caps how many of them are read ahead.
+	Workers     int `env:"KAFKA_WORKERS" envDefault:"8"`
+	MaxInFlight int `env:"KAFKA_MAX_IN_FLIGHT" envDefault:"256"`
+
+	// BatchSize above one switches to batch mode: up to BatchSize messages,
+	// or whatever arrived within BatchWait, are saved together. It is capped
+	// at 100, so a batch is saved in one transaction.
+	BatchSize int           `env:"KAFKA_BATCH_SIZE" envDefault:"0"`
+	BatchWait time.Duration `env:"KAFKA_BATCH_WAIT" envDefault:"100ms"`
+
+	// DrainTimeout bounds how long in-flight messages may take on shutdown.
+	// Messages not done by then are delivered again after a restart.
+	DrainTimeout time.Duration `env:"KAFKA_DRAIN_TIMEOUT" envDefault:"30s"`
+}
+
+// Validate rejects an unknown retry jitter, so a typo does not silently fall
+// back to full jitter.
+func (c KafkaConfig) Validate() error {
+	if !retry.Jitter(c.RetryJitter).Valid() {
+		return fmt.Errorf(
+			"KAFKA_RETRY_JITTER must be %q, %q or %q, got %q",
+			retry.JitterFull, retry.JitterEqual, retry.JitterDecorrelated, c.RetryJitter,
+		)
+	}
+
+	return nil
+}
+
+type CacheConfig struct {
+	MaxEntries int           `env:"CACHE_MAX_ENTRIES" envDefault:"100000"`
+	MaxBytes   int64         `env:"CACHE_MAX_BYTES" envDefault:"268435456"`
+	TTL        time.Duration `env:"CACHE_TTL" envDefault:"1h"`
+	Shards     int           `env:"CACHE_SHARDS" envDefault:"16"`
+
+	// LoadTimeout bounds a database load shared by concurrent misses, which
+	// outlives the request that started it.
+	LoadTimeout time.Duration `env:"CACHE_LOAD_TIMEOUT" envDefault:"5s"`
+
+	NegativeTTL        time.Duration `env:"CACHE_NEGATIVE_TTL" envDefault:"5s"`
+	NegativeMaxEntries int           `env:"CACHE_NEGATIVE_MAX_ENTRIES" envDefault:"10000"`
+
+	WarmupWindow   time.Duration `env:"CACHE_WARMUP_WINDOW"`
+	WarmupLimit    int           `env:"CACHE_WARMUP_LIMIT" envDefault:"100000"`
+	WarmupPageSize int           `env:"CACHE_WARMUP_PAGE_SIZE" envDefault:"1000"`
+}
+
+// OutboxConfig controls the relay that publishes order events from the
+// outbox table to Kafka.
+type OutboxConfig struct {
+	Topic        string        `env:"OUTBOX_TOPIC" envDefault:"order-events"`
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	BatchSize    int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	// Sent events are deleted once they are older than Retention.
+	Retention       time.Duration `env:"OUTBOX_RETENTION" envDefault:"24h"`
+	CleanupInterval time.Duration `env:"OUTBOX_CLEANUP_INTERVAL" envDefault:"1h"`
+	// ClaimTimeout is how long a relay may take to publish the events it
+	// claimed before another relay may claim them again.
+	ClaimTimeout time.Duration `env:"OUTBOX_CLAIM_TIMEOUT" envDefault:"30s"`
+	// StopTimeout bounds how long a batch being published may take on
+	// shutdown.
+	StopTimeout time.Duration `env:"OUTBOX_STOP_TIMEOUT" envDefault:"10s"`
+}
+
+const (
+	ValidationStrict = "strict"
+	ValidationWarn   = "warn"
+)
+
+// ValidationConfig controls the business consistency checks. In "strict" mode
+// inconsistent orders are rejected, in "warn" mode they are only logged.
+type ValidationConfig struct {
+	Mode string `env:"VALIDATION_MODE" envDefault:"warn"`
+}
+
+func (c ValidationConfig) Strict() bool {
+	return c.Mode == ValidationStrict
+}
+
+// Validate rejects unknown modes, so a typo does not silently fall back to
+// warn mode.
+func (c ValidationConfig) Validate() error {
+	switch c.Mode {
+	case ValidationStrict, ValidationWarn:
+		return nil
+	default:
+		return fmt.Errorf("VALIDATION_MODE must be %q or %q, got %q", ValidationStrict, ValidationWarn, c.Mode)
+	}
 }
 
 func LoadConfig() (*Config, error) {
@@ -54,5 +163,13 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.Kafka.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validation.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
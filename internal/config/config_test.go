@@ -0,0 +1,61 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidationConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{mode: ValidationStrict},
+		{mode: ValidationWarn},
+		{mode: "STRICT", wantErr: true},
+		{mode: "strcit", wantErr: true},
+		{mode: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidationConfig{Mode: tt.mode}.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestKafkaConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		jitter  string
+		wantErr bool
+	}{
+		{jitter: "full"},
+		{jitter: "equal"},
+		{jitter: "decorrelated"},
+		{jitter: "Full", wantErr: true},
+		{jitter: "decorelated", wantErr: true},
+		{jitter: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jitter, func(t *testing.T) {
+			t.Parallel()
+
+			err := KafkaConfig{RetryJitter: tt.jitter}.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/sarama"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/service"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// consumeBatches collects up to batchSize messages, or whatever arrived
+// within batchWait of the first one, and saves them together. Offsets are
+// marked after the batch is committed, up to the first message that could
+// not be finished. A batch collected when the consumer stops is still saved.
+func (h *Handler) consumeBatches(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx, cancel := h.claimContext(session)
+	defer cancel()
+
+	tracker := newOffsetTracker(session)
+	batch := make([]*sarama.ConsumerMessage, 0, h.batchSize)
+
+	timer := time.NewTimer(h.batchWait)
+	timer.Stop()
+	defer timer.Stop()
+
+	flush := func() {
+		timer.Stop()
+		if len(batch) == 0 {
+			return
+		}
+
+		if ctx.Err() == nil {
+			for i, done := range h.processBatch(ctx, batch) {
+				if done {
+					tracker.complete(batch[i])
+				}
+			}
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				flush()
+				return nil
+			}
+			h.log.Info(
+				"message received",
+				slog.String("topic", msg.Topic),
+				slog.Int("partition", int(msg.Partition)),
+				slog.Int64("offset", msg.Offset),
+			)
+
+			tracker.add(msg)
+			batch = append(batch, msg)
+			if len(batch) == 1 {
+				timer.Reset(h.batchWait)
+			}
+			if len(batch) >= h.batchSize {
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		case <-session.Context().Done():
+			if h.stopping() {
+				flush()
+			}
+			return nil
+		}
+	}
+}
+
+// processBatch saves the orders of msgs with one AddOrders call and reports
+// for each message whether it is finished with. If the batch as a whole
+// fails, or single orders in it fail, those messages are processed one by
+// one, so a bad order does not hold back the rest.
+func (h *Handler) processBatch(ctx context.Context, msgs []*sarama.ConsumerMessage) []bool {
+	done := make([]bool, len(msgs))
+	orders := make([]*models.Order, 0, len(msgs))
+	index := make([]int, 0, len(msgs))
+
+	for i, msg := range msgs {
+		var order models.Order
+		if err := json.Unmarshal(msg.Value, &order); err != nil {
+			h.log.Error("unmarshal failed", slog.Any("error", err))
+			done[i] = h.fail(ctx, msg, classPermanent, 1, err)
+			continue
+		}
+		orders = append(orders, &order)
+		index = append(index, i)
+	}
+
+	if len(orders) == 0 {
+		return done
+	}
+
+	results, err := h.svc.AddOrders(ctx, orders)
+	if err != nil {
+		h.log.Warn("batch failed, processing messages one by one",
+			slog.Int("size", len(orders)),
+			slog.Any("error", err),
+		)
+		for _, i := range index {
+			done[i] = h.processMessage(ctx, msgs[i])
+		}
+		return done
+	}
+
+	for _, res := range results {
+		i := index[res.Index]
+
+		switch res.Status {
+		case models.BatchCreated, models.BatchDuplicate:
+			done[i] = true
+		case models.BatchInvalid:
+			h.log.Error(
+				"order rejected by validation",
+				slog.String("order_uid", res.OrderUID),
+				slog.Any("errors", res.Errors),
+			)
+			cause := fmt.Errorf("%w: %s", service.ErrInvalidInput, strings.Join(res.Errors, "; "))
+			done[i] = h.fail(ctx, msgs[i], classPermanent, 1, cause)
+		default:
+			done[i] = h.processMessage(ctx, msgs[i])
+		}
+	}
+
+	h.log.Info("batch processed", slog.Int("size", len(orders)))
+	return done
+}
@@ -0,0 +1,168 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+var ErrBatch = errors.New("batch failed")
+
+// batchService answers AddOrders with the statuses set per order uid and
+// records every call.
+type batchService struct {
+	fakeService
+	statuses map[string]models.BatchStatus
+	err      error
+
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (s *batchService) AddOrders(_ context.Context, orders []*models.Order) ([]models.BatchResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uids := make([]string, 0, len(orders))
+	for _, o := range orders {
+		uids = append(uids, o.OrderUID)
+	}
+	s.batches = append(s.batches, uids)
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	results := make([]models.BatchResult, len(orders))
+	for i, o := range orders {
+		status, ok := s.statuses[o.OrderUID]
+		if !ok {
+			status = models.BatchCreated
+		}
+		results[i] = models.BatchResult{Index: i, OrderUID: o.OrderUID, Status: status}
+		if status == models.BatchInvalid {
+			results[i].Errors = []string{"payment.currency: must be an ISO 4217 code"}
+		}
+	}
+	return results, nil
+}
+
+func (s *batchService) saved() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func TestHandler_ConsumeClaim_Batches(t *testing.T) {
+	t.Parallel()
+
+	svc := &batchService{}
+	h := &Handler{svc: svc, log: slog.Default(), batchSize: 2, batchWait: time.Hour}
+	session := &fakeSession{ctx: context.Background()}
+
+	claim := newClaim(`{"order_uid":"uid1"}`, `{"order_uid":"uid2"}`, `{"order_uid":"uid3"}`)
+	require.NoError(t, h.ConsumeClaim(session, claim))
+
+	assert.Equal(t, [][]string{{"uid1", "uid2"}, {"uid3"}}, svc.saved())
+	assert.Equal(t, []int64{10, 11, 12}, session.marked)
+	assert.Zero(t, svc.fakeService.calls)
+}
+
+func TestHandler_ConsumeClaim_FlushesAfterWait(t *testing.T) {
+	t.Parallel()
+
+	svc := &batchService{}
+	h := &Handler{svc: svc, log: slog.Default(), batchSize: 10, batchWait: 10 * time.Millisecond}
+	session := &fakeSession{ctx: context.Background()}
+
+	claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, 2)}
+	claim.msgs <- &sarama.ConsumerMessage{Offset: 0, Value: []byte(`{"order_uid":"uid1"}`)}
+	claim.msgs <- &sarama.ConsumerMessage{Offset: 1, Value: []byte(`{"order_uid":"uid2"}`)}
+
+	done := make(chan error)
+	go func() { done <- h.ConsumeClaim(session, claim) }()
+
+	assert.Eventually(t, func() bool {
+		session.mu.Lock()
+		defer session.mu.Unlock()
+		return len(session.marked) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	close(claim.msgs)
+	require.NoError(t, <-done)
+	assert.Equal(t, [][]string{{"uid1", "uid2"}}, svc.saved())
+}
+
+func TestHandler_ConsumeClaim_BatchFallsBack(t *testing.T) {
+	t.Parallel()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	t.Cleanup(func() { _ = producer.Close() })
+
+	var letters []map[string]string
+	capture := func(msg *sarama.ProducerMessage) error {
+		letters = append(letters, headerMap(msg))
+		return nil
+	}
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(capture)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(capture)
+
+	svc := &batchService{statuses: map[string]models.BatchStatus{
+		"uid2": models.BatchDuplicate,
+		"uid3": models.BatchInvalid,
+		"uid4": models.BatchFailed,
+	}}
+	h := &Handler{svc: svc, log: slog.Default(), dlq: producer, dlqTopic: "orders-dlq", batchSize: 5, batchWait: time.Hour}
+	session := &fakeSession{ctx: context.Background()}
+
+	claim := newClaim(
+		`{"order_uid":"uid1"}`,
+		`{not json`,
+		`{"order_uid":"uid2"}`,
+		`{"order_uid":"uid3"}`,
+		`{"order_uid":"uid4"}`,
+	)
+	require.NoError(t, h.ConsumeClaim(session, claim))
+
+	assert.Equal(t, [][]string{{"uid1", "uid2", "uid3", "uid4"}}, svc.saved())
+	assert.Equal(t, 1, svc.fakeService.calls, "failed order is retried on its own")
+	assert.Equal(t, []int64{10, 11, 12, 13, 14}, session.marked)
+
+	require.Len(t, letters, 2)
+	assert.Equal(t, "11", letters[0][HeaderOriginalOffset])
+	assert.Equal(t, "13", letters[1][HeaderOriginalOffset])
+	assert.Contains(t, letters[1][HeaderError], "ISO 4217")
+}
+
+func TestHandler_processBatch_ErrorFallsBack(t *testing.T) {
+	t.Parallel()
+
+	svc := &batchService{err: ErrBatch}
+	h := &Handler{svc: svc, log: slog.Default()}
+
+	msgs := []*sarama.ConsumerMessage{
+		{Offset: 0, Value: []byte(`{"order_uid":"uid1"}`)},
+		{Offset: 1, Value: []byte(`{"order_uid":"uid2"}`)},
+	}
+	done := h.processBatch(context.Background(), msgs)
+
+	assert.Equal(t, []bool{true, true}, done)
+	assert.Equal(t, 2, svc.fakeService.calls)
+}
+
+func Test_capBatchSize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, capBatchSize(0, slog.Default()))
+	assert.Equal(t, 50, capBatchSize(50, slog.Default()))
+	assert.Equal(t, service.BatchChunkSize, capBatchSize(service.BatchChunkSize+1, slog.Default()))
+}
@@ -3,27 +3,26 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/IBM/sarama"
 	"github.com/sdvaanyaa/order-service/internal/config"
 	"github.com/sdvaanyaa/order-service/internal/models"
 	"github.com/sdvaanyaa/order-service/internal/service"
+	"github.com/sdvaanyaa/order-service/pkg/retry"
 	"log/slog"
-	"math"
-	"math/rand"
 	"time"
 )
 
-const (
-	MaxAddOrderRetries = 5
-	BaseDelay          = 1 * time.Second
-	MaxAddOrderDelay   = 30 * time.Second
-	MaxConsumeDelay    = 60 * time.Second
-	BackoffFactor      = 2
-)
+const MaxConsumeDelay = 60 * time.Second
 
 type Consumer interface {
 	Run(ctx context.Context)
 	Ready() <-chan bool
+	// Stop stops fetching, lets in-flight messages finish and commits their
+	// offsets, then closes the group. If ctx ends before the messages are
+	// done they are abandoned and delivered again later.
+	Stop(ctx context.Context) error
 }
 
 type kafkaConsumer struct {
@@ -31,12 +30,32 @@ type kafkaConsumer struct {
 	handler *Handler
 	log     *slog.Logger
 	topics  []string
+	// backoff keeps growing across failed Consume calls until one succeeds
+	backoff *retry.Backoff
+
+	stopping  context.Context
+	stopFetch context.CancelFunc
+	abort     context.CancelFunc
+	done      chan struct{}
 }
 
 type Handler struct {
-	svc   service.OrderService
-	log   *slog.Logger
-	ready chan bool
+	svc      service.OrderService
+	log      *slog.Logger
+	ready    chan bool
+	dlq      sarama.SyncProducer
+	dlqTopic string
+	retry    retry.Policy
+
+	workers     int
+	maxInFlight int
+	batchSize   int
+	batchWait   time.Duration
+
+	// stop is closed when the consumer stops fetching, abort when draining
+	// the in-flight messages takes too long
+	stop  <-chan struct{}
+	abort <-chan struct{}
 }
 
 func New(cfg config.KafkaConfig, svc service.OrderService, log *slog.Logger) (Consumer, error) {
@@ -50,23 +69,85 @@ func New(cfg config.KafkaConfig, svc service.OrderService, log *slog.Logger) (Co
 		return nil, err
 	}
 
+	var dlq sarama.SyncProducer
+	if cfg.DLQEnabled {
+		dlq, err = newDLQProducer(cfg.Brokers)
+		if err != nil {
+			_ = group.Close()
+			return nil, err
+		}
+	}
+
+	backoff := retry.Config{
+		Base:   cfg.RetryBaseDelay,
+		Max:    cfg.RetryMaxDelay,
+		Jitter: retry.Jitter(cfg.RetryJitter),
+	}
+
+	stopping, stopFetch := context.WithCancel(context.Background())
+	aborted, abort := context.WithCancel(context.Background())
+
 	return &kafkaConsumer{
 		group: group,
 		handler: &Handler{
-			svc:   svc,
-			log:   log,
-			ready: make(chan bool),
+			svc:      svc,
+			log:      log,
+			ready:    make(chan bool),
+			dlq:      dlq,
+			dlqTopic: cfg.DLQTopic,
+			retry: retry.Policy{
+				Backoff:        backoff,
+				MaxAttempts:    cfg.RetryMaxAttempts,
+				AttemptTimeout: cfg.AttemptTimeout,
+			},
+			workers:     cfg.Workers,
+			maxInFlight: cfg.MaxInFlight,
+			batchSize:   capBatchSize(cfg.BatchSize, log),
+			batchWait:   cfg.BatchWait,
+			stop:        stopping.Done(),
+			abort:       aborted.Done(),
 		},
 		log:    log,
 		topics: []string{cfg.Topic},
+		backoff: retry.NewBackoff(retry.Config{
+			Base:   cfg.RetryBaseDelay,
+			Max:    MaxConsumeDelay,
+			Jitter: backoff.Jitter,
+		}),
+		stopping:  stopping,
+		stopFetch: stopFetch,
+		abort:     abort,
+		done:      make(chan struct{}),
 	}, nil
 }
 
+// capBatchSize keeps a batch within one AddOrders chunk, so it is saved in
+// a single transaction.
+func capBatchSize(size int, log *slog.Logger) int {
+	if size <= service.BatchChunkSize {
+		return size
+	}
+
+	log.Warn("kafka batch size capped at the chunk size",
+		slog.Int("batch_size", size),
+		slog.Int("chunk_size", service.BatchChunkSize),
+	)
+	return service.BatchChunkSize
+}
+
 func (c *kafkaConsumer) Ready() <-chan bool {
 	return c.handler.ready
 }
 
 func (c *kafkaConsumer) Run(ctx context.Context) {
+	defer close(c.done)
+
+	// stopping ends the session, but its messages are drained before
+	// Consume returns
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(c.stopping, cancel)()
+
 	go func() {
 		for err := range c.group.Errors() {
 			c.log.Error("kafka group error", slog.Any("error", err))
@@ -75,97 +156,149 @@ func (c *kafkaConsumer) Run(ctx context.Context) {
 
 	for {
 		if err := c.group.Consume(ctx, c.topics, c.handler); err != nil {
-			c.log.Error("kafka consume failed", slog.Any("error", err))
-			delay := backoffDelay(1, BaseDelay, MaxConsumeDelay)
-			time.Sleep(delay)
+			delay := c.backoff.Next()
+			c.log.Error("kafka consume failed",
+				slog.Int("attempt", c.backoff.Attempt()),
+				slog.Duration("retry_in", delay),
+				slog.Any("error", err),
+			)
+			_ = retry.Wait(ctx, delay)
+		} else {
+			c.backoff.Reset()
+		}
+		if ctx.Err() != nil {
+			break
 		}
-		select {
-		case <-ctx.Done():
-			c.log.Info("Kafka consumer stopping due to context cancellation")
-			if err := c.group.Close(); err != nil {
-				c.log.Error("failed to close Kafka consumer group", slog.Any("error", err))
-			}
-			return
-		default:
-			c.handler.ready = make(chan bool)
+		c.handler.ready = make(chan bool)
+	}
+
+	c.log.Info("Kafka consumer stopping")
+	if err := c.group.Close(); err != nil {
+		c.log.Error("failed to close Kafka consumer group", slog.Any("error", err))
+	}
+	if c.handler.dlq != nil {
+		if err := c.handler.dlq.Close(); err != nil {
+			c.log.Error("failed to close dead-letter producer", slog.Any("error", err))
 		}
 	}
 }
 
+func (c *kafkaConsumer) Stop(ctx context.Context) error {
+	c.stopFetch()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		c.abort()
+		<-c.done
+		return fmt.Errorf("drain kafka consumer: %w", ctx.Err())
+	}
+}
+
 func (h *Handler) Setup(sarama.ConsumerGroupSession) error {
 	h.log.Info("consumer setup complete, ready to consume")
 	close(h.ready)
 	return nil
 }
 
-func (h *Handler) Cleanup(sarama.ConsumerGroupSession) error {
+// Cleanup commits the offsets marked while draining right away instead of
+// leaving them to the next auto-commit.
+func (h *Handler) Cleanup(session sarama.ConsumerGroupSession) error {
+	if h.stopping() {
+		session.Commit()
+	}
 	return nil
 }
 
-func (h *Handler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	for {
-		select {
-		case msg, ok := <-claim.Messages():
-			if !ok {
-				return nil
-			}
-			h.log.Info(
-				"message received",
-				slog.String("topic", msg.Topic),
-				slog.Int("partition", int(msg.Partition)),
-				slog.Int64("offset", msg.Offset),
-			)
-			h.processMessage(session, msg)
-		case <-session.Context().Done():
-			return nil
-		}
+func (h *Handler) stopping() bool {
+	select {
+	case <-h.stop:
+		return true
+	default:
+		return false
 	}
 }
 
-func (h *Handler) processMessage(session sarama.ConsumerGroupSession, msg *sarama.ConsumerMessage) {
+// processMessage stores the order from msg and reports whether the message
+// is finished with, either processed or dead-lettered, and may be marked.
+func (h *Handler) processMessage(ctx context.Context, msg *sarama.ConsumerMessage) bool {
 	var order models.Order
 	if err := json.Unmarshal(msg.Value, &order); err != nil {
 		h.log.Error("unmarshal failed", slog.Any("error", err))
-		session.MarkMessage(msg, "")
-		return
+		return h.fail(ctx, msg, classPermanent, 1, err)
 	}
 
 	h.log.Info("processing order", slog.String("order_uid", order.OrderUID))
 
-	err := h.tryAddOrder(session.Context(), &order)
+	attempts, err := h.tryAddOrder(ctx, &order)
 	if err != nil {
-		h.log.Error("add order failed after retries", slog.Any("error", err))
-		// consider DLQ in prod
-	} else {
-		h.log.Info("order processed", slog.String("order_uid", order.OrderUID))
+		h.logFailure(&order, err)
+		return h.fail(ctx, msg, classify(err), attempts, err)
 	}
 
-	session.MarkMessage(msg, "")
+	h.log.Info("order processed", slog.String("order_uid", order.OrderUID))
+	return true
 }
 
-func (h *Handler) tryAddOrder(ctx context.Context, order *models.Order) error {
-	attempt := 0
-	var addErr error
-	for attempt < MaxAddOrderRetries {
-		addErr = h.svc.AddOrder(ctx, order)
-		if addErr == nil {
-			return nil
-		}
+func (h *Handler) logFailure(order *models.Order, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		h.log.Error(
+			"order rejected by validation",
+			slog.String("order_uid", order.OrderUID),
+			slog.Any("fields", verr.Fields),
+		)
+		return
+	}
+
+	h.log.Error(
+		"add order failed",
+		slog.String("order_uid", order.OrderUID),
+		slog.String("class", string(classify(err))),
+		slog.Any("error", err),
+	)
+}
 
-		attempt++
-		h.log.Warn("add order retry", slog.Int("attempt", attempt), slog.Any("error", addErr))
-		delay := backoffDelay(attempt, BaseDelay, MaxAddOrderDelay)
-		time.Sleep(delay)
+// fail dead-letters the message and reports whether that succeeded. If ctx
+// ends first, e.g. on shutdown or rebalance, the message must stay unmarked
+// so it is delivered again.
+func (h *Handler) fail(
+	ctx context.Context,
+	msg *sarama.ConsumerMessage,
+	class errorClass,
+	attempts int,
+	cause error,
+) bool {
+	if ctx.Err() != nil {
+		return false
 	}
-	return addErr
+
+	return h.deadLetter(ctx, msg, class, attempts, cause) == nil
 }
 
-func backoffDelay(attempt int, base, max time.Duration) time.Duration {
-	exp := math.Pow(BackoffFactor, float64(attempt-1))
-	delay := time.Duration(exp) * base
-	if delay > max {
-		delay = max
+// tryAddOrder stores the order, retrying only transient errors, and returns
+// how many attempts it took. A duplicate means the message was processed
+// before and counts as success.
+func (h *Handler) tryAddOrder(ctx context.Context, order *models.Order) (int, error) {
+	policy := h.retry
+	policy.Retryable = func(err error) bool {
+		return classify(err) == classTransient
+	}
+	policy.OnRetry = func(attempt int, err error, delay time.Duration) {
+		h.log.Warn("add order retry",
+			slog.Int("attempt", attempt),
+			slog.Duration("retry_in", delay),
+			slog.Any("error", err),
+		)
 	}
-	jitter := time.Duration(rand.Int63n(int64(delay)))
-	return jitter
+
+	return retry.Do(ctx, policy, func(ctx context.Context) error {
+		err := h.svc.AddOrder(ctx, order)
+		if err != nil && classify(err) == classDuplicate {
+			h.log.Info("order already stored, skipping", slog.String("order_uid", order.OrderUID))
+			return nil
+		}
+		return err
+	})
 }
@@ -0,0 +1,89 @@
+package consumer
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/service"
+	"github.com/sdvaanyaa/order-service/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeService answers AddOrder with the queued errors and counts the calls.
+type fakeService struct {
+	service.OrderService
+	errs  []error
+	calls int
+}
+
+func (f *fakeService) AddOrder(_ context.Context, _ *models.Order) error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func TestHandler_tryAddOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "Success",
+			wantCalls: 1,
+		},
+		{
+			name:      "Duplicate Is Success",
+			errs:      []error{service.ErrOrderAlreadyExists},
+			wantCalls: 1,
+		},
+		{
+			name:      "Transient Retried",
+			errs:      []error{io.ErrUnexpectedEOF, context.DeadlineExceeded},
+			wantCalls: 3,
+		},
+		{
+			name:      "Transient Out Of Attempts",
+			errs:      []error{io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF},
+			wantErr:   io.ErrUnexpectedEOF,
+			wantCalls: 3,
+		},
+		{
+			name:      "Permanent Not Retried",
+			errs:      []error{service.ErrInvalidInput},
+			wantErr:   service.ErrInvalidInput,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &fakeService{errs: tt.errs}
+			h := &Handler{
+				svc: svc,
+				log: slog.Default(),
+				retry: retry.Policy{
+					Backoff:     retry.Config{Base: time.Microsecond, Max: time.Millisecond},
+					MaxAttempts: 3,
+				},
+			}
+
+			attempts, err := h.tryAddOrder(context.Background(), &models.Order{OrderUID: "uid1"})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantCalls, svc.calls)
+			assert.Equal(t, tt.wantCalls, attempts)
+		})
+	}
+}
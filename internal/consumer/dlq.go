@@ -0,0 +1,119 @@
+package consumer
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/sdvaanyaa/order-service/pkg/retry"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// Headers added to dead letters on top of the original ones.
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderErrorClass        = "x-error-class"
+	HeaderError             = "x-error"
+	HeaderAttempts          = "x-attempts"
+	HeaderFailedAt          = "x-failed-at"
+)
+
+// newDLQProducer connects the producer that publishes dead letters. Every
+// letter waits for all in-sync replicas, since the source offset is marked
+// right after.
+func newDLQProducer(brokers []string) (sarama.SyncProducer, error) {
+	conf := sarama.NewConfig()
+	conf.Producer.RequiredAcks = sarama.WaitForAll
+	conf.Producer.Return.Successes = true
+
+	return sarama.NewSyncProducer(brokers, conf)
+}
+
+// deadLetter publishes msg to the DLQ topic, retrying until it succeeds or
+// ctx is done. Without a DLQ topic the message is only logged as dropped.
+func (h *Handler) deadLetter(
+	ctx context.Context,
+	msg *sarama.ConsumerMessage,
+	class errorClass,
+	attempts int,
+	cause error,
+) error {
+	if h.dlq == nil {
+		h.log.Warn("no dead-letter topic configured, dropping message",
+			slog.String("topic", msg.Topic),
+			slog.Int("partition", int(msg.Partition)),
+			slog.Int64("offset", msg.Offset),
+		)
+		return nil
+	}
+
+	letter := deadLetterMessage(h.dlqTopic, msg, class, attempts, cause, time.Now())
+
+	policy := retry.Policy{
+		Backoff: h.retry.Backoff,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			h.log.Error("dead-letter publish failed",
+				slog.Int("attempt", attempt),
+				slog.Duration("retry_in", delay),
+				slog.Any("error", err),
+			)
+		},
+	}
+
+	_, err := retry.Do(ctx, policy, func(context.Context) error {
+		partition, offset, err := h.dlq.SendMessage(letter)
+		if err != nil {
+			return err
+		}
+
+		h.log.Info("message dead-lettered",
+			slog.String("topic", h.dlqTopic),
+			slog.Int("partition", int(partition)),
+			slog.Int64("offset", offset),
+		)
+		return nil
+	})
+
+	return err
+}
+
+func deadLetterMessage(
+	topic string,
+	msg *sarama.ConsumerMessage,
+	class errorClass,
+	attempts int,
+	cause error,
+	failedAt time.Time,
+) *sarama.ProducerMessage {
+	headers := make([]sarama.RecordHeader, 0, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers = append(headers, *h)
+	}
+
+	headers = append(headers,
+		header(HeaderOriginalTopic, msg.Topic),
+		header(HeaderOriginalPartition, strconv.Itoa(int(msg.Partition))),
+		header(HeaderOriginalOffset, strconv.FormatInt(msg.Offset, 10)),
+		header(HeaderErrorClass, string(class)),
+		header(HeaderError, cause.Error()),
+		header(HeaderAttempts, strconv.Itoa(attempts)),
+		header(HeaderFailedAt, failedAt.UTC().Format(time.RFC3339Nano)),
+	)
+
+	out := &sarama.ProducerMessage{
+		Topic:   topic,
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: headers,
+	}
+	if msg.Key != nil {
+		out.Key = sarama.ByteEncoder(msg.Key)
+	}
+
+	return out
+}
+
+func header(key, value string) sarama.RecordHeader {
+	return sarama.RecordHeader{Key: []byte(key), Value: []byte(value)}
+}
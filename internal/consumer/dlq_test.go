@@ -0,0 +1,195 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/sdvaanyaa/order-service/internal/service"
+	"github.com/sdvaanyaa/order-service/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+var ErrBroker = errors.New("broker down")
+
+// fakeSession records marked messages.
+type fakeSession struct {
+	ctx    context.Context
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *fakeSession) Claims() map[string][]int32               { return nil }
+func (s *fakeSession) MemberID() string                         { return "member" }
+func (s *fakeSession) GenerationID() int32                      { return 1 }
+func (s *fakeSession) MarkOffset(string, int32, int64, string)  {}
+func (s *fakeSession) Commit()                                  {}
+func (s *fakeSession) ResetOffset(string, int32, int64, string) {}
+func (s *fakeSession) Context() context.Context                 { return s.ctx }
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, msg.Offset)
+}
+
+type fakeClaim struct {
+	msgs chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return "orders" }
+func (c *fakeClaim) Partition() int32                         { return 3 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.msgs }
+
+func newClaim(values ...string) *fakeClaim {
+	claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, len(values))}
+	for i, v := range values {
+		claim.msgs <- &sarama.ConsumerMessage{
+			Topic:     "orders",
+			Partition: 3,
+			Offset:    int64(10 + i),
+			Key:       []byte("key"),
+			Value:     []byte(v),
+			Headers:   []*sarama.RecordHeader{{Key: []byte("trace-id"), Value: []byte("abc")}},
+		}
+	}
+	close(claim.msgs)
+	return claim
+}
+
+func headerMap(msg *sarama.ProducerMessage) map[string]string {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[string(h.Key)] = string(h.Value)
+	}
+	return headers
+}
+
+func TestHandler_ConsumeClaim_DeadLetters(t *testing.T) {
+	t.Parallel()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	t.Cleanup(func() { _ = producer.Close() })
+
+	var letters []*sarama.ProducerMessage
+	capture := func(msg *sarama.ProducerMessage) error {
+		letters = append(letters, msg)
+		return nil
+	}
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(capture)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(capture)
+
+	svc := &fakeService{errs: []error{nil, service.ErrInvalidInput}}
+	h := &Handler{svc: svc, log: slog.Default(), dlq: producer, dlqTopic: "orders-dlq"}
+	session := &fakeSession{ctx: context.Background()}
+
+	before := time.Now().UTC()
+	err := h.ConsumeClaim(session, newClaim(`{"order_uid":"uid1"}`, `{"order_uid":"uid2"}`, `{not json`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{10, 11, 12}, session.marked)
+	require.Len(t, letters, 2)
+
+	invalid := letters[0]
+	assert.Equal(t, "orders-dlq", invalid.Topic)
+	value, _ := invalid.Value.Encode()
+	assert.JSONEq(t, `{"order_uid":"uid2"}`, string(value))
+	key, _ := invalid.Key.Encode()
+	assert.Equal(t, "key", string(key))
+
+	headers := headerMap(invalid)
+	assert.Equal(t, "abc", headers["trace-id"])
+	assert.Equal(t, "orders", headers[HeaderOriginalTopic])
+	assert.Equal(t, "3", headers[HeaderOriginalPartition])
+	assert.Equal(t, "11", headers[HeaderOriginalOffset])
+	assert.Equal(t, string(classPermanent), headers[HeaderErrorClass])
+	assert.Equal(t, service.ErrInvalidInput.Error(), headers[HeaderError])
+	assert.Equal(t, "1", headers[HeaderAttempts])
+	failedAt, err := time.Parse(time.RFC3339Nano, headers[HeaderFailedAt])
+	require.NoError(t, err)
+	assert.False(t, failedAt.Before(before))
+
+	malformed := headerMap(letters[1])
+	assert.Equal(t, strconv.Itoa(12), malformed[HeaderOriginalOffset])
+	assert.Equal(t, string(classPermanent), malformed[HeaderErrorClass])
+	assert.Contains(t, malformed[HeaderError], "invalid character")
+}
+
+func TestHandler_processMessage_KeepsUnpublishedLetter(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// the session ends while the broker is down
+	producer := mocks.NewSyncProducer(t, nil)
+	t.Cleanup(func() { _ = producer.Close() })
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndFail(func(*sarama.ProducerMessage) error {
+		cancel()
+		return nil
+	}, ErrBroker)
+
+	h := &Handler{
+		svc:      &fakeService{},
+		log:      slog.Default(),
+		dlq:      producer,
+		dlqTopic: "orders-dlq",
+		retry:    retry.Policy{Backoff: retry.Config{Base: time.Hour}},
+	}
+
+	done := h.processMessage(ctx, &sarama.ConsumerMessage{Topic: "orders", Offset: 7, Value: []byte(`{not json`)})
+
+	assert.False(t, done)
+}
+
+func TestHandler_processMessage_NoDLQ(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{svc: &fakeService{}, log: slog.Default()}
+
+	done := h.processMessage(
+		context.Background(),
+		&sarama.ConsumerMessage{Topic: "orders", Offset: 7, Value: []byte(`{not json`)},
+	)
+
+	assert.True(t, done)
+}
+
+func TestHandler_deadLetter_Broker(t *testing.T) {
+	t.Parallel()
+
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("orders-dlq", 0, broker.BrokerID()),
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"ProduceRequest":     sarama.NewMockProduceResponse(t),
+	})
+
+	producer, err := newDLQProducer([]string{broker.Addr()})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = producer.Close() })
+
+	h := &Handler{svc: &fakeService{}, log: slog.Default(), dlq: producer, dlqTopic: "orders-dlq"}
+	msg := &sarama.ConsumerMessage{Topic: "orders", Partition: 3, Offset: 7, Value: []byte(`{not json`)}
+
+	require.True(t, h.processMessage(context.Background(), msg))
+
+	var produced []*sarama.ProduceRequest
+	for _, rr := range broker.History() {
+		if req, ok := rr.Request.(*sarama.ProduceRequest); ok {
+			produced = append(produced, req)
+		}
+	}
+	require.Len(t, produced, 1)
+	assert.Equal(t, sarama.WaitForAll, produced[0].RequiredAcks)
+}
@@ -0,0 +1,67 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sdvaanyaa/order-service/internal/service"
+	"io"
+	"net"
+)
+
+type errorClass string
+
+const (
+	// classTransient errors may go away on their own: lost connections,
+	// timeouts, deadlocks, an overloaded database.
+	classTransient errorClass = "transient"
+	// classPermanent errors fail the same way every time, so the message
+	// is given up on at once.
+	classPermanent errorClass = "permanent"
+	// classDuplicate means the order is stored already, so a redelivered
+	// message has nothing left to do. A payment transaction reused by another
+	// order is not a duplicate but a permanent error.
+	classDuplicate errorClass = "duplicate"
+)
+
+// SQLSTATE classes worth retrying: connection exceptions, transaction
+// rollbacks (serialization failures, deadlocks), insufficient resources,
+// operator intervention and system errors.
+var transientSQLStates = map[string]struct{}{
+	"08": {},
+	"40": {},
+	"53": {},
+	"57": {},
+	"58": {},
+}
+
+func classify(err error) errorClass {
+	switch {
+	case errors.Is(err, service.ErrOrderAlreadyExists):
+		return classDuplicate
+	case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrTransactionUsed):
+		return classPermanent
+	case isTransient(err):
+		return classTransient
+	default:
+		return classPermanent
+	}
+}
+
+func isTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && len(pgErr.Code) >= 2 {
+		_, ok := transientSQLStates[pgErr.Code[:2]]
+		return ok
+	}
+
+	var netErr net.Error
+	var connectErr *pgconn.ConnectError
+
+	return errors.As(err, &netErr) ||
+		errors.As(err, &connectErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		pgconn.Timeout(err) ||
+		pgconn.SafeToRetry(err)
+}
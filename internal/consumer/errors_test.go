@@ -0,0 +1,86 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sdvaanyaa/order-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"testing"
+)
+
+func Test_classify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want errorClass
+	}{
+		{
+			name: "Duplicate",
+			err:  fmt.Errorf("transaction failed: %w", service.ErrOrderAlreadyExists),
+			want: classDuplicate,
+		},
+		{
+			name: "Payment Transaction Reused",
+			err:  service.ErrTransactionUsed,
+			want: classPermanent,
+		},
+		{
+			name: "Invalid Input",
+			err:  service.ErrInvalidInput,
+			want: classPermanent,
+		},
+		{
+			name: "Validation Error",
+			err:  &service.ValidationError{Fields: []service.FieldError{{Field: "order_uid", Rule: "required"}}},
+			want: classPermanent,
+		},
+		{
+			name: "Serialization Failure",
+			err:  fmt.Errorf("transaction failed: %w", &pgconn.PgError{Code: "40001"}),
+			want: classTransient,
+		},
+		{
+			name: "Too Many Connections",
+			err:  &pgconn.PgError{Code: "53300"},
+			want: classTransient,
+		},
+		{
+			name: "Check Violation",
+			err:  &pgconn.PgError{Code: "23514"},
+			want: classPermanent,
+		},
+		{
+			name: "Network",
+			err:  &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			want: classTransient,
+		},
+		{
+			name: "Connection Dropped",
+			err:  fmt.Errorf("query: %w", io.ErrUnexpectedEOF),
+			want: classTransient,
+		},
+		{
+			name: "Timeout",
+			err:  context.DeadlineExceeded,
+			want: classTransient,
+		},
+		{
+			name: "Unknown",
+			err:  errors.New("boom"),
+			want: classPermanent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, classify(tt.err))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+)
+
+// ConsumeClaim spreads the messages of a claim over a pool of workers. All
+// messages of one order go to the same worker, so they are handled in order.
+// At most maxInFlight messages are handed out at once; when workers are
+// saturated the claim is not read any further. Offsets are marked only up to
+// the first message that has not completed yet. With a batch size above one
+// the claim is consumed in batches instead.
+func (h *Handler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	if h.batchSize > 1 {
+		return h.consumeBatches(session, claim)
+	}
+
+	ctx, cancel := h.claimContext(session)
+	defer cancel()
+
+	workers := max(h.workers, 1)
+	slots := make(chan struct{}, max(h.maxInFlight, workers))
+	tracker := newOffsetTracker(session)
+
+	queues := make([]chan *sarama.ConsumerMessage, workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan *sarama.ConsumerMessage, cap(slots))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.work(ctx, queues[i], slots, tracker)
+		}()
+	}
+	defer func() {
+		for _, q := range queues {
+			close(q)
+		}
+		wg.Wait()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			h.log.Info(
+				"message received",
+				slog.String("topic", msg.Topic),
+				slog.Int("partition", int(msg.Partition)),
+				slog.Int64("offset", msg.Offset),
+			)
+
+			select {
+			case slots <- struct{}{}:
+			case <-session.Context().Done():
+				return nil
+			}
+
+			tracker.add(msg)
+			queues[route(msg, workers)] <- msg
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+// work processes its queue in order. Once ctx is over the remaining messages
+// are skipped and left unmarked.
+func (h *Handler) work(
+	ctx context.Context,
+	queue <-chan *sarama.ConsumerMessage,
+	slots <-chan struct{},
+	tracker *offsetTracker,
+) {
+	for msg := range queue {
+		if ctx.Err() == nil && h.processMessage(ctx, msg) {
+			tracker.complete(msg)
+		}
+		<-slots
+	}
+}
+
+// claimContext returns the context the messages of a claim are processed
+// with. It ends with the session, except while the consumer is stopping:
+// then the messages already handed out may finish until the drain is aborted.
+func (h *Handler) claimContext(session sarama.ConsumerGroupSession) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(session.Context()))
+
+	go func() {
+		select {
+		case <-session.Context().Done():
+		case <-ctx.Done():
+			return
+		}
+
+		if h.stopping() {
+			select {
+			case <-h.abort:
+			case <-ctx.Done():
+			}
+		}
+		cancel()
+	}()
+
+	return ctx, cancel
+}
+
+// route picks the worker for a message by its order uid. Messages without
+// one cannot be processed anyway and all go to the first worker.
+func route(msg *sarama.ConsumerMessage, workers int) int {
+	var key struct {
+		OrderUID string `json:"order_uid"`
+	}
+	if json.Unmarshal(msg.Value, &key) != nil || key.OrderUID == "" {
+		return 0
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key.OrderUID))
+	return int(h.Sum32() % uint32(workers))
+}
+
+// offsetTracker marks messages in the order they were received, each only
+// after it and every message before it have completed.
+type offsetTracker struct {
+	mu      sync.Mutex
+	session sarama.ConsumerGroupSession
+	pending []*sarama.ConsumerMessage
+	done    map[int64]struct{}
+}
+
+func newOffsetTracker(session sarama.ConsumerGroupSession) *offsetTracker {
+	return &offsetTracker{
+		session: session,
+		done:    make(map[int64]struct{}),
+	}
+}
+
+func (t *offsetTracker) add(msg *sarama.ConsumerMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending = append(t.pending, msg)
+}
+
+func (t *offsetTracker) complete(msg *sarama.ConsumerMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done[msg.Offset] = struct{}{}
+
+	for len(t.pending) > 0 {
+		head := t.pending[0]
+		if _, ok := t.done[head.Offset]; !ok {
+			return
+		}
+
+		t.session.MarkMessage(head, "")
+		delete(t.done, head.Offset)
+		t.pending[0] = nil
+		t.pending = t.pending[1:]
+	}
+}
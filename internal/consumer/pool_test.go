@@ -0,0 +1,228 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"github.com/IBM/sarama"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingService records the orders it is given and calls hook with each.
+type blockingService struct {
+	service.OrderService
+	hook func(order *models.Order)
+
+	mu     sync.Mutex
+	stored []string
+}
+
+func (s *blockingService) AddOrder(_ context.Context, order *models.Order) error {
+	if s.hook != nil {
+		s.hook(order)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stored = append(s.stored, order.OrderUID+"/"+order.Entry)
+	return nil
+}
+
+func orderClaim(entries ...[2]string) *fakeClaim {
+	claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, len(entries))}
+	for i, e := range entries {
+		claim.msgs <- &sarama.ConsumerMessage{
+			Topic:  "orders",
+			Offset: int64(i),
+			Value:  []byte(fmt.Sprintf(`{"order_uid":%q,"entry":%q}`, e[0], e[1])),
+		}
+	}
+	close(claim.msgs)
+	return claim
+}
+
+func TestOffsetTracker(t *testing.T) {
+	t.Parallel()
+
+	session := &fakeSession{ctx: context.Background()}
+	tracker := newOffsetTracker(session)
+
+	msgs := make([]*sarama.ConsumerMessage, 4)
+	for i := range msgs {
+		msgs[i] = &sarama.ConsumerMessage{Offset: int64(i)}
+		tracker.add(msgs[i])
+	}
+
+	tracker.complete(msgs[1])
+	tracker.complete(msgs[2])
+	assert.Empty(t, session.marked)
+
+	tracker.complete(msgs[0])
+	assert.Equal(t, []int64{0, 1, 2}, session.marked)
+
+	tracker.complete(msgs[3])
+	assert.Equal(t, []int64{0, 1, 2, 3}, session.marked)
+}
+
+func TestHandler_ConsumeClaim_KeepsOrderPerOrder(t *testing.T) {
+	t.Parallel()
+
+	svc := &blockingService{hook: func(*models.Order) {
+		time.Sleep(time.Duration(rand.IntN(2000)) * time.Microsecond)
+	}}
+	h := &Handler{svc: svc, log: slog.Default(), workers: 4, maxInFlight: 8}
+	session := &fakeSession{ctx: context.Background()}
+
+	var entries [][2]string
+	for i := range 10 {
+		for _, uid := range []string{"a", "b", "c", "d", "e"} {
+			entries = append(entries, [2]string{uid, fmt.Sprint(i)})
+		}
+	}
+
+	require.NoError(t, h.ConsumeClaim(session, orderClaim(entries...)))
+
+	perOrder := make(map[string][]string)
+	for _, stored := range svc.stored {
+		perOrder[stored[:1]] = append(perOrder[stored[:1]], stored[2:])
+	}
+	for uid, seq := range perOrder {
+		assert.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, seq, uid)
+	}
+
+	want := make([]int64, len(entries))
+	for i := range want {
+		want[i] = int64(i)
+	}
+	assert.Equal(t, want, session.marked)
+}
+
+func TestHandler_ConsumeClaim_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+	svc := &blockingService{hook: func(*models.Order) {
+		started <- struct{}{}
+		<-release
+	}}
+	h := &Handler{svc: svc, log: slog.Default(), workers: 2, maxInFlight: 2}
+	session := &fakeSession{ctx: context.Background()}
+	claim := orderClaim([2]string{"a", "0"}, [2]string{"b", "0"}, [2]string{"c", "0"}, [2]string{"d", "0"})
+
+	done := make(chan error)
+	go func() { done <- h.ConsumeClaim(session, claim) }()
+
+	<-started
+	<-started
+	time.Sleep(20 * time.Millisecond)
+
+	// two in flight, the third read and waiting for a slot, the last unread
+	assert.Len(t, claim.msgs, 1)
+	assert.Len(t, started, 0)
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, []int64{0, 1, 2, 3}, session.marked)
+}
+
+func TestHandler_ConsumeClaim_SessionEnds(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	svc := &blockingService{hook: func(order *models.Order) {
+		if order.OrderUID == "b" {
+			cancel()
+		}
+	}}
+	h := &Handler{svc: svc, log: slog.Default(), workers: 1, maxInFlight: 1}
+	session := &fakeSession{ctx: ctx}
+
+	claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, 3)}
+	for i, uid := range []string{"a", "b", "c"} {
+		claim.msgs <- &sarama.ConsumerMessage{Offset: int64(i), Value: []byte(fmt.Sprintf(`{"order_uid":%q}`, uid))}
+	}
+
+	require.NoError(t, h.ConsumeClaim(session, claim))
+
+	assert.Contains(t, session.marked, int64(0))
+	assert.NotContains(t, session.marked, int64(2))
+}
+
+// drainService blocks AddOrder until it is released or ctx ends.
+type drainService struct {
+	service.OrderService
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *drainService) AddOrder(ctx context.Context, _ *models.Order) error {
+	s.started <- struct{}{}
+	select {
+	case <-s.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestHandler_ConsumeClaim_Drain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		stop       bool
+		finish     func(release, abort chan struct{})
+		wantMarked []int64
+	}{
+		{
+			name:       "Stopping Drains In-Flight Messages",
+			stop:       true,
+			finish:     func(release, _ chan struct{}) { close(release) },
+			wantMarked: []int64{0},
+		},
+		{
+			name:   "Abort Cuts The Drain Short",
+			stop:   true,
+			finish: func(_, abort chan struct{}) { close(abort) },
+		},
+		{
+			name:   "Rebalance Cancels In-Flight Messages",
+			finish: func(chan struct{}, chan struct{}) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &drainService{started: make(chan struct{}, 1), release: make(chan struct{})}
+			stop, abort := make(chan struct{}), make(chan struct{})
+			h := &Handler{svc: svc, log: slog.Default(), workers: 1, maxInFlight: 1, stop: stop, abort: abort}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			session := &fakeSession{ctx: ctx}
+			claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, 1)}
+			claim.msgs <- &sarama.ConsumerMessage{Offset: 0, Value: []byte(`{"order_uid":"a"}`)}
+
+			done := make(chan error)
+			go func() { done <- h.ConsumeClaim(session, claim) }()
+			<-svc.started
+
+			if tt.stop {
+				close(stop)
+			}
+			cancel()
+			tt.finish(svc.release, abort)
+
+			require.NoError(t, <-done)
+			assert.Equal(t, tt.wantMarked, session.marked)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"strings"
+)
+
+const MaxBatchSize = 5000
+
+var (
+	errEmptyBatch    = errors.New("empty batch")
+	errBatchTooLarge = errors.New("batch too large")
+)
+
+func (h *Handler) AddOrdersBatch(c *fiber.Ctx) error {
+	raw, err := splitBatch(c.Body(), strings.Contains(c.Get(fiber.HeaderContentType), "ndjson"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	results := make([]models.BatchResult, len(raw))
+	orders := make([]*models.Order, 0, len(raw))
+	indexes := make([]int, 0, len(raw))
+
+	for i, msg := range raw {
+		var order models.Order
+		if err = json.Unmarshal(msg, &order); err != nil {
+			results[i] = models.BatchResult{Index: i, Status: models.BatchInvalid, Errors: []string{"invalid JSON"}}
+			continue
+		}
+		orders = append(orders, &order)
+		indexes = append(indexes, i)
+	}
+
+	saved, err := h.svc.AddOrders(c.Context(), orders)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	counts := make(map[models.BatchStatus]int)
+	for j, res := range saved {
+		res.Index = indexes[j]
+		results[res.Index] = res
+	}
+	for _, res := range results {
+		counts[res.Status]++
+	}
+
+	return c.JSON(fiber.Map{"results": results, "summary": counts})
+}
+
+// splitBatch accepts either a JSON array or newline-delimited JSON and
+// returns the raw orders so each one can fail decoding on its own.
+func splitBatch(body []byte, ndjson bool) ([]json.RawMessage, error) {
+	body = bytes.TrimSpace(body)
+
+	var raw []json.RawMessage
+	if !ndjson && bytes.HasPrefix(body, []byte("[")) {
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, errors.New("invalid JSON")
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(body)+1)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) > 0 {
+				raw = append(raw, json.RawMessage(bytes.Clone(line)))
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case len(raw) == 0:
+		return nil, errEmptyBatch
+	case len(raw) > MaxBatchSize:
+		return nil, errBatchTooLarge
+	}
+
+	return raw, nil
+}
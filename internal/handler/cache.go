@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sdvaanyaa/order-service/internal/service"
+)
+
+func (h *Handler) CacheStats(c *fiber.Ctx) error {
+	return c.JSON(h.svc.CacheStats())
+}
+
+func (h *Handler) ReloadCache(c *fiber.Ctx) error {
+	if err := h.svc.ReloadCache(); err != nil {
+		if errors.Is(err, service.ErrWarmupRunning) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"status": "reloading"})
+}
+
+func (h *Handler) EvictCachedOrder(c *fiber.Ctx) error {
+	h.svc.EvictOrder(c.Params("uid"))
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,29 +1,56 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/gofiber/fiber/v2"
+	"github.com/sdvaanyaa/order-service/internal/config"
 	"github.com/sdvaanyaa/order-service/internal/middleware"
 	"github.com/sdvaanyaa/order-service/internal/models"
 	"github.com/sdvaanyaa/order-service/internal/repository"
 	"github.com/sdvaanyaa/order-service/internal/service"
 	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Handler struct {
-	svc service.OrderService
+	svc            service.OrderService
+	idempotency    repository.IdempotencyRepository
+	adminToken     string
+	idempotencyTTL time.Duration
 }
 
-func New(svc service.OrderService) *Handler {
-	return &Handler{svc: svc}
+func New(svc service.OrderService, idempotency repository.IdempotencyRepository, cfg config.HTTPConfig) *Handler {
+	return &Handler{
+		svc:            svc,
+		idempotency:    idempotency,
+		adminToken:     cfg.AdminToken,
+		idempotencyTTL: cfg.IdempotencyTTL,
+	}
 }
 
 func (h *Handler) SetupRoutes(app *fiber.App, log *slog.Logger) {
 	app.Use(middleware.Logging(log))
-	app.Post("/order", h.AddOrder)
+	app.Post("/order", middleware.Idempotency(h.idempotency, h.idempotencyTTL, log), h.AddOrder)
 	app.Get("/order/:uid", h.GetOrder)
+	app.Patch("/order/:uid", h.UpdateOrder)
+	app.Delete("/order/:uid", h.DeleteOrder)
+	app.Post("/order/:uid/status", h.ChangeOrderStatus)
+	app.Get("/orders", h.ListOrders)
+	app.Post("/orders/batch", h.AddOrdersBatch)
+	app.Get("/orders/by-track/:track_number", h.GetOrdersByTrackNumber)
+	app.Get("/customers/:customer_id/orders", h.GetCustomerOrders)
+	app.Get("/health", h.Health)
 	app.Get("/", h.Index)
+
+	admin := app.Group("/admin", middleware.AdminAuth(h.adminToken))
+	admin.Delete("/orders/:uid", h.HardDeleteOrder)
+	admin.Get("/cache/stats", h.CacheStats)
+	admin.Post("/cache/reload", h.ReloadCache)
+	admin.Delete("/cache/:uid", h.EvictCachedOrder)
 }
 
 func (h *Handler) AddOrder(c *fiber.Ctx) error {
@@ -35,8 +62,8 @@ func (h *Handler) AddOrder(c *fiber.Ctx) error {
 	if err := h.svc.AddOrder(c.Context(), &order); err != nil {
 		switch {
 		case errors.Is(err, service.ErrInvalidInput):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
-		case errors.Is(err, service.ErrOrderAlreadyExists):
+			return invalidInput(c, err)
+		case errors.Is(err, service.ErrOrderAlreadyExists), errors.Is(err, service.ErrTransactionUsed):
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
@@ -49,7 +76,7 @@ func (h *Handler) AddOrder(c *fiber.Ctx) error {
 func (h *Handler) GetOrder(c *fiber.Ctx) error {
 	uid := c.Params("uid")
 
-	order, err := h.svc.GetOrder(c.Context(), uid)
+	order, err := h.svc.GetOrder(c.Context(), uid, c.QueryBool("include_deleted"))
 	if err != nil {
 		if errors.Is(err, repository.ErrOrderNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "order not found"})
@@ -57,9 +84,164 @@ func (h *Handler) GetOrder(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
 	}
 
+	c.Set(fiber.HeaderETag, etag(order.Version))
+	return c.JSON(order)
+}
+
+func (h *Handler) UpdateOrder(c *fiber.Ctx) error {
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch == "" {
+		return c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{"error": "If-Match header required"})
+	}
+
+	version, err := parseETag(ifMatch)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid If-Match header"})
+	}
+
+	var patch models.OrderPatch
+	if err = json.Unmarshal(c.Body(), &patch); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid JSON"})
+	}
+
+	order, err := h.svc.UpdateOrder(c.Context(), c.Params("uid"), patch, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			return invalidInput(c, err)
+		case errors.Is(err, repository.ErrOrderNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "order not found"})
+		case errors.Is(err, service.ErrVersionConflict):
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrOrderAlreadyExists), errors.Is(err, service.ErrTransactionUsed):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+		}
+	}
+
+	c.Set(fiber.HeaderETag, etag(order.Version))
+	return c.JSON(order)
+}
+
+func (h *Handler) DeleteOrder(c *fiber.Ctx) error {
+	return h.deleteOrder(c, h.svc.DeleteOrder)
+}
+
+func (h *Handler) HardDeleteOrder(c *fiber.Ctx) error {
+	return h.deleteOrder(c, h.svc.HardDeleteOrder)
+}
+
+func (h *Handler) deleteOrder(c *fiber.Ctx, del func(ctx context.Context, uid string) error) error {
+	if err := del(c.Context(), c.Params("uid")); err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "order not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) ChangeOrderStatus(c *fiber.Ctx) error {
+	var req struct {
+		Status models.OrderStatus `json:"status"`
+	}
+
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid JSON"})
+	}
+
+	order, err := h.svc.ChangeOrderStatus(c.Context(), c.Params("uid"), req.Status)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unknown status"})
+		case errors.Is(err, repository.ErrOrderNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "order not found"})
+		case errors.Is(err, service.ErrInvalidTransition):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+		}
+	}
+
+	c.Set(fiber.HeaderETag, etag(order.Version))
 	return c.JSON(order)
 }
 
+func (h *Handler) ListOrders(c *fiber.Ctx) error {
+	filter := models.OrderFilter{
+		CustomerID:      c.Query("customer_id"),
+		DeliveryService: c.Query("delivery_service"),
+		Locale:          c.Query("locale"),
+	}
+
+	var err error
+	if filter.DateFrom, err = parseTimeQuery(c, "date_from"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid date_from"})
+	}
+	if filter.DateTo, err = parseTimeQuery(c, "date_to"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid date_to"})
+	}
+
+	page, err := h.svc.ListOrders(c.Context(), filter, c.Query("cursor"), c.QueryInt("limit"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	return c.JSON(page)
+}
+
+func (h *Handler) GetOrdersByTrackNumber(c *fiber.Ctx) error {
+	orders, err := h.svc.GetOrdersByTrackNumber(c.Context(), c.Params("track_number"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, repository.ErrOrderNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "order not found"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+		}
+	}
+
+	return c.JSON(orders)
+}
+
+func (h *Handler) GetCustomerOrders(c *fiber.Ctx) error {
+	result, err := h.svc.GetCustomerOrders(c.Context(), c.Params("customer_id"), c.Query("cursor"), c.QueryInt("limit"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	return c.JSON(result)
+}
+
 func (h *Handler) Index(c *fiber.Ctx) error {
 	return c.SendFile("./static/index.html")
 }
+
+func parseTimeQuery(c *fiber.Ctx, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func parseETag(value string) (int, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	return strconv.Atoi(strings.Trim(value, `"`))
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sdvaanyaa/order-service/internal/models"
+)
+
+// Health reports readiness. The service is not ready while the cache is
+// warming at startup. A reload keeps it ready and a failed warmup only
+// degrades it, since reads still go to the database.
+func (h *Handler) Health(c *fiber.Ctx) error {
+	warmup := h.svc.WarmupStatus()
+
+	switch {
+	case warmup.State == models.WarmupReady, warmup.Reload:
+		return c.JSON(fiber.Map{"status": "ok", "cache": warmup})
+	case warmup.State == models.WarmupFailed:
+		return c.JSON(fiber.Map{"status": "degraded", "cache": warmup})
+	default:
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"status": "warming", "cache": warmup})
+	}
+}
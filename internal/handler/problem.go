@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sdvaanyaa/order-service/internal/service"
+)
+
+const problemContentType = "application/problem+json"
+
+// problem is an RFC 7807 error body.
+type problem struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Instance string               `json:"instance,omitempty"`
+	Errors   []service.FieldError `json:"errors,omitempty"`
+}
+
+// invalidInput answers 400. Validation errors are rendered as problem+json
+// listing every failed field, anything else as a plain error.
+func invalidInput(c *fiber.Ctx, err error) error {
+	var verr *service.ValidationError
+	if !errors.As(err, &verr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusBadRequest).JSON(problem{
+		Type:     "about:blank",
+		Title:    "Invalid order",
+		Status:   fiber.StatusBadRequest,
+		Detail:   "the order failed validation",
+		Instance: c.Path(),
+		Errors:   verr.Fields,
+	}, problemContentType)
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/gofiber/fiber/v2"
+)
+
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminAuth lets through only requests carrying the configured admin token.
+// An empty token disables the admin routes entirely.
+func AdminAuth(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		got := c.Get(AdminTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	"log/slog"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	MaxIdempotencyKeyLength  = 255
+)
+
+// Idempotency replays the stored response when a request is retried with
+// the same Idempotency-Key and body. Reusing a key for a different request
+// is rejected. Server errors are not stored, so such requests can be retried.
+func Idempotency(store repository.IdempotencyRepository, ttl time.Duration, log *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > MaxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "idempotency key too long"})
+		}
+
+		hash := requestHash(c)
+
+		existing, reserved, err := store.ReserveKey(c.Context(), key, hash, ttl)
+		if err != nil {
+			log.Error("idempotency key reserve failed", slog.Any("error", err))
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+		}
+		if !reserved {
+			return replay(c, existing, hash)
+		}
+
+		if err = c.Next(); err != nil {
+			releaseKey(c, store, key, log)
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			releaseKey(c, store, key, log)
+			return nil
+		}
+
+		body := bytes.Clone(c.Response().Body())
+		if err = store.CompleteKey(c.Context(), key, status, body); err != nil {
+			log.Error("idempotency key complete failed", slog.String("key", key), slog.Any("error", err))
+		}
+
+		return nil
+	}
+}
+
+func replay(c *fiber.Ctx, record *models.IdempotencyRecord, hash string) error {
+	if record.RequestHash != hash {
+		return c.Status(fiber.StatusUnprocessableEntity).
+			JSON(fiber.Map{"error": "idempotency key reused with a different request"})
+	}
+
+	if !record.Completed() {
+		return c.Status(fiber.StatusConflict).
+			JSON(fiber.Map{"error": "request with this idempotency key is in progress"})
+	}
+
+	c.Set(IdempotentReplayedHeader, "true")
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	return c.Status(record.StatusCode).Send(record.Body)
+}
+
+func releaseKey(c *fiber.Ctx, store repository.IdempotencyRepository, key string, log *slog.Logger) {
+	if err := store.ReleaseKey(c.Context(), key); err != nil {
+		log.Error("idempotency key release failed", slog.String("key", key), slog.Any("error", err))
+	}
+}
+
+func requestHash(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method()))
+	h.Write([]byte(c.Path()))
+	h.Write(c.Body())
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyRecord
+}
+
+func (m *memoryIdempotencyStore) ReserveKey(
+	_ context.Context,
+	key, requestHash string,
+	_ time.Duration,
+) (*models.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.records[key]; ok {
+		copied := *existing
+		return &copied, false, nil
+	}
+	m.records[key] = &models.IdempotencyRecord{Key: key, RequestHash: requestHash}
+
+	return nil, true, nil
+}
+
+func (m *memoryIdempotencyStore) CompleteKey(_ context.Context, key string, statusCode int, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[key].StatusCode = statusCode
+	m.records[key].Body = body
+
+	return nil
+}
+
+func (m *memoryIdempotencyStore) ReleaseKey(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records, key)
+
+	return nil
+}
+
+func (m *memoryIdempotencyStore) DeleteExpiredKeys(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		key  string
+		body string
+	}
+	tests := []struct {
+		name         string
+		status       int
+		requests     []request
+		wantStatuses []int
+		wantCalls    int
+		wantReplayed bool
+	}{
+		{
+			name:         "Same Key Same Body Replays",
+			status:       fiber.StatusCreated,
+			requests:     []request{{"k1", `{"a":1}`}, {"k1", `{"a":1}`}},
+			wantStatuses: []int{fiber.StatusCreated, fiber.StatusCreated},
+			wantCalls:    1,
+			wantReplayed: true,
+		},
+		{
+			name:         "Same Key Different Body Rejected",
+			status:       fiber.StatusCreated,
+			requests:     []request{{"k1", `{"a":1}`}, {"k1", `{"a":2}`}},
+			wantStatuses: []int{fiber.StatusCreated, fiber.StatusUnprocessableEntity},
+			wantCalls:    1,
+		},
+		{
+			name:         "Server Error Not Stored",
+			status:       fiber.StatusInternalServerError,
+			requests:     []request{{"k1", `{"a":1}`}, {"k1", `{"a":1}`}},
+			wantStatuses: []int{fiber.StatusInternalServerError, fiber.StatusInternalServerError},
+			wantCalls:    2,
+		},
+		{
+			name:         "No Key Passes Through",
+			status:       fiber.StatusConflict,
+			requests:     []request{{"", `{"a":1}`}, {"", `{"a":1}`}},
+			wantStatuses: []int{fiber.StatusConflict, fiber.StatusConflict},
+			wantCalls:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &memoryIdempotencyStore{records: make(map[string]*models.IdempotencyRecord)}
+			calls := 0
+
+			app := fiber.New()
+			app.Post("/order", Idempotency(store, time.Hour, slog.Default()), func(c *fiber.Ctx) error {
+				calls++
+				return c.Status(tt.status).JSON(fiber.Map{"call": calls})
+			})
+
+			var bodies []string
+			var replayed bool
+			for i, r := range tt.requests {
+				req := httptest.NewRequest(fiber.MethodPost, "/order", strings.NewReader(r.body))
+				if r.key != "" {
+					req.Header.Set(IdempotencyKeyHeader, r.key)
+				}
+
+				resp, err := app.Test(req)
+				require.NoError(t, err)
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				_ = resp.Body.Close()
+
+				assert.Equal(t, tt.wantStatuses[i], resp.StatusCode)
+				bodies = append(bodies, string(body))
+				replayed = resp.Header.Get(IdempotentReplayedHeader) == "true"
+			}
+
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantReplayed, replayed)
+			if tt.wantReplayed {
+				assert.Equal(t, bodies[0], bodies[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+type BatchStatus string
+
+const (
+	BatchCreated   BatchStatus = "created"
+	BatchDuplicate BatchStatus = "duplicate"
+	BatchInvalid   BatchStatus = "invalid"
+	BatchFailed    BatchStatus = "failed"
+)
+
+type BatchResult struct {
+	Index    int         `json:"index"`
+	OrderUID string      `json:"order_uid,omitempty"`
+	Status   BatchStatus `json:"status"`
+	Errors   []string    `json:"errors,omitempty"`
+}
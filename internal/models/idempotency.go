@@ -0,0 +1,14 @@
+package models
+
+// IdempotencyRecord is a stored Idempotency-Key. StatusCode is zero while
+// the original request is still being processed.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Body        []byte
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -3,20 +3,23 @@ package models
 import "time"
 
 type Order struct {
-	OrderUID          string    `json:"order_uid" validate:"required"`
-	TrackNumber       string    `json:"track_number" validate:"required"`
-	Entry             string    `json:"entry"`
-	Delivery          Delivery  `json:"delivery" validate:"required"`
-	Payment           Payment   `json:"payment" validate:"required"`
-	Items             []Item    `json:"items" validate:"required,dive"`
-	Locale            string    `json:"locale" validate:"required"`
-	InternalSignature string    `json:"internal_signature"`
-	CustomerID        string    `json:"customer_id" validate:"required"`
-	DeliveryService   string    `json:"delivery_service" validate:"required"`
-	Shardkey          string    `json:"shardkey" validate:"required"`
-	SmID              int       `json:"sm_id" validate:"min=0"`
-	DateCreated       time.Time `json:"date_created" validate:"required"`
-	OofShard          string    `json:"oof_shard" validate:"required"`
+	OrderUID          string      `json:"order_uid" validate:"required"`
+	TrackNumber       string      `json:"track_number" validate:"required"`
+	Entry             string      `json:"entry"`
+	Delivery          Delivery    `json:"delivery" validate:"required"`
+	Payment           Payment     `json:"payment" validate:"required"`
+	Items             []Item      `json:"items" validate:"required,dive"`
+	Locale            string      `json:"locale" validate:"required"`
+	InternalSignature string      `json:"internal_signature"`
+	CustomerID        string      `json:"customer_id" validate:"required"`
+	DeliveryService   string      `json:"delivery_service" validate:"required"`
+	Shardkey          string      `json:"shardkey" validate:"required"`
+	SmID              int         `json:"sm_id" validate:"min=0"`
+	DateCreated       time.Time   `json:"date_created" validate:"required"`
+	OofShard          string      `json:"oof_shard" validate:"required"`
+	Status            OrderStatus `json:"status"`
+	Version           int         `json:"version"`
+	DeletedAt         *time.Time  `json:"deleted_at,omitempty"`
 }
 
 type Delivery struct {
@@ -55,3 +58,10 @@ type Item struct {
 	Brand       string `json:"brand" validate:"required"`
 	Status      int    `json:"status" validate:"min=0"`
 }
+
+const percent = 100
+
+// DiscountedPrice is the price of the item after its sale percentage.
+func (i Item) DiscountedPrice() int {
+	return i.Price * (percent - i.Sale) / percent
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// OrderFilter narrows down order listings. Empty fields are ignored.
+type OrderFilter struct {
+	CustomerID      string
+	DeliveryService string
+	Locale          string
+	DateFrom        time.Time
+	DateTo          time.Time
+}
+
+// OrderCursor points at the last order of a page. Orders are sorted by
+// (date_created, order_uid) descending, so the pair is unique and stable.
+type OrderCursor struct {
+	DateCreated time.Time `json:"date_created"`
+	OrderUID    string    `json:"order_uid"`
+}
+
+type OrderPage struct {
+	Orders     []*Order `json:"orders"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+type CustomerSummary struct {
+	CustomerID   string           `json:"customer_id"`
+	OrderCount   int              `json:"order_count"`
+	TotalSpent   map[string]int64 `json:"total_spent"`
+	FirstOrderAt *time.Time       `json:"first_order_at,omitempty"`
+	LastOrderAt  *time.Time       `json:"last_order_at,omitempty"`
+}
+
+type CustomerOrders struct {
+	Summary *CustomerSummary `json:"summary"`
+	OrderPage
+}
+
+// LoadOptions limits which orders are loaded in bulk, e.g. for cache warmup.
+// Orders come newest first; After continues from a previous page. Zero
+// values mean no limit.
+type LoadOptions struct {
+	IncludeDeleted bool
+	Since          time.Time
+	After          *OrderCursor
+	Limit          int
+}
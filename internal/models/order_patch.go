@@ -0,0 +1,94 @@
+package models
+
+// OrderPatch describes a partial order update. Nil fields are left untouched.
+type OrderPatch struct {
+	Delivery    *DeliveryPatch `json:"delivery,omitempty"`
+	Payment     *PaymentPatch  `json:"payment,omitempty"`
+	AddItems    []Item         `json:"add_items,omitempty"`
+	RemoveItems []int64        `json:"remove_items,omitempty"`
+}
+
+type DeliveryPatch struct {
+	Name    *string `json:"name,omitempty"`
+	Phone   *string `json:"phone,omitempty"`
+	Zip     *string `json:"zip,omitempty"`
+	City    *string `json:"city,omitempty"`
+	Address *string `json:"address,omitempty"`
+	Region  *string `json:"region,omitempty"`
+	Email   *string `json:"email,omitempty"`
+}
+
+type PaymentPatch struct {
+	Transaction  *string `json:"transaction,omitempty"`
+	RequestID    *string `json:"request_id,omitempty"`
+	Currency     *string `json:"currency,omitempty"`
+	Provider     *string `json:"provider,omitempty"`
+	Amount       *int    `json:"amount,omitempty"`
+	PaymentDt    *int64  `json:"payment_dt,omitempty"`
+	Bank         *string `json:"bank,omitempty"`
+	DeliveryCost *int    `json:"delivery_cost,omitempty"`
+	GoodsTotal   *int    `json:"goods_total,omitempty"`
+	CustomFee    *int    `json:"custom_fee,omitempty"`
+}
+
+func (p OrderPatch) Empty() bool {
+	return p.Delivery == nil && p.Payment == nil && len(p.AddItems) == 0 && len(p.RemoveItems) == 0
+}
+
+// Apply returns a copy of order with the patch applied. Items listed in
+// RemoveItems are matched by chrt_id.
+func (p OrderPatch) Apply(order *Order) *Order {
+	patched := *order
+
+	if p.Delivery != nil {
+		p.Delivery.apply(&patched.Delivery)
+	}
+
+	if p.Payment != nil {
+		p.Payment.apply(&patched.Payment)
+	}
+
+	removed := make(map[int64]struct{}, len(p.RemoveItems))
+	for _, id := range p.RemoveItems {
+		removed[id] = struct{}{}
+	}
+
+	patched.Items = make([]Item, 0, len(order.Items)+len(p.AddItems))
+	for _, item := range order.Items {
+		if _, ok := removed[item.ChrtID]; !ok {
+			patched.Items = append(patched.Items, item)
+		}
+	}
+	patched.Items = append(patched.Items, p.AddItems...)
+
+	return &patched
+}
+
+func (p *DeliveryPatch) apply(d *Delivery) {
+	set(&d.Name, p.Name)
+	set(&d.Phone, p.Phone)
+	set(&d.Zip, p.Zip)
+	set(&d.City, p.City)
+	set(&d.Address, p.Address)
+	set(&d.Region, p.Region)
+	set(&d.Email, p.Email)
+}
+
+func (p *PaymentPatch) apply(pay *Payment) {
+	set(&pay.Transaction, p.Transaction)
+	set(&pay.RequestID, p.RequestID)
+	set(&pay.Currency, p.Currency)
+	set(&pay.Provider, p.Provider)
+	set(&pay.Amount, p.Amount)
+	set(&pay.PaymentDt, p.PaymentDt)
+	set(&pay.Bank, p.Bank)
+	set(&pay.DeliveryCost, p.DeliveryCost)
+	set(&pay.GoodsTotal, p.GoodsTotal)
+	set(&pay.CustomFee, p.CustomFee)
+}
+
+func set[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	EventOrderCreated = "order.created"
+	EventOrderUpdated = "order.updated"
+)
+
+// OutboxEvent is stored in the same transaction as the change it describes
+// and published to Kafka afterwards.
+type OutboxEvent struct {
+	ID        int64
+	EventID   string
+	OrderUID  string
+	Type      string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// OrderEvent is the payload of order events. Events may be delivered more
+// than once and, across replicas, out of order: consumers should skip ids
+// they have seen and versions older than the one they hold.
+type OrderEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OrderUID   string    `json:"order_uid"`
+	Version    int       `json:"version"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Order      *Order    `json:"order"`
+}
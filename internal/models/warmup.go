@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type WarmupState string
+
+const (
+	WarmupPending WarmupState = "pending"
+	WarmupRunning WarmupState = "warming"
+	WarmupReady   WarmupState = "ready"
+	WarmupFailed  WarmupState = "failed"
+)
+
+// WarmupStatus reports the progress of the cache warmup.
+type WarmupStatus struct {
+	State      WarmupState `json:"state"`
+	Loaded     int         `json:"loaded"`
+	StartedAt  *time.Time  `json:"started_at,omitempty"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	// Reload is set while an admin reload warms the cache again
+	Reload bool `json:"reload,omitempty"`
+}
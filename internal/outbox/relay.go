@@ -0,0 +1,180 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/IBM/sarama"
+	"github.com/sdvaanyaa/order-service/internal/config"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	"log/slog"
+	"time"
+)
+
+const (
+	HeaderEventID   = "x-event-id"
+	HeaderEventType = "x-event-type"
+)
+
+// Relay publishes the events written to the outbox to Kafka. Events are
+// claimed for a while, published and then marked sent, only after the broker
+// acknowledged them. Events whose claim runs out unsent are claimed again,
+// so every event is published at least once.
+type Relay struct {
+	repo     repository.OutboxRepository
+	producer sarama.SyncProducer
+	log      *slog.Logger
+	cfg      config.OutboxConfig
+
+	stopping context.Context
+	stop     context.CancelFunc
+	done     chan struct{}
+}
+
+func New(
+	cfg config.OutboxConfig,
+	brokers []string,
+	repo repository.OutboxRepository,
+	log *slog.Logger,
+) (*Relay, error) {
+	pconf := sarama.NewConfig()
+	pconf.Producer.RequiredAcks = sarama.WaitForAll
+	pconf.Producer.Return.Successes = true
+
+	producer, err := sarama.NewSyncProducer(brokers, pconf)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRelay(cfg, repo, producer, log), nil
+}
+
+func newRelay(
+	cfg config.OutboxConfig,
+	repo repository.OutboxRepository,
+	producer sarama.SyncProducer,
+	log *slog.Logger,
+) *Relay {
+	stopping, stop := context.WithCancel(context.Background())
+
+	return &Relay{
+		repo:     repo,
+		producer: producer,
+		log:      log,
+		cfg:      cfg,
+		stopping: stopping,
+		stop:     stop,
+		done:     make(chan struct{}),
+	}
+}
+
+// Run publishes pending events every poll interval and deletes old sent
+// events every cleanup interval until ctx ends or Stop is called.
+func (r *Relay) Run(ctx context.Context) {
+	defer close(r.done)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(r.stopping, cancel)()
+
+	poll := time.NewTicker(r.cfg.PollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(r.cfg.CleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			r.publishPending(ctx)
+		case <-cleanup.C:
+			r.cleanup(ctx)
+		}
+	}
+}
+
+// Stop ends Run and closes the producer, also when the batch being
+// published does not finish in time. Such a batch stays unsent and is
+// claimed again once its claim runs out.
+func (r *Relay) Stop(ctx context.Context) error {
+	r.stop()
+
+	var err error
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		err = fmt.Errorf("stop outbox relay: %w", ctx.Err())
+	}
+
+	return errors.Join(err, r.producer.Close())
+}
+
+// publishPending publishes batches until the outbox is drained or a batch
+// fails. A failed batch is retried on the next poll.
+func (r *Relay) publishPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := r.publishBatch(ctx)
+		if err != nil {
+			r.log.Error("outbox publish failed", slog.Any("error", err))
+			return
+		}
+		if n > 0 {
+			r.log.Info("outbox events published", slog.Int("count", n))
+		}
+		if n < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// publishBatch claims the oldest pending events, publishes them and marks
+// them sent. If publishing fails the events stay pending and are claimed
+// again once the claim runs out.
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	events, err := r.repo.ClaimPending(ctx, r.cfg.BatchSize, r.cfg.ClaimTimeout)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	msgs := make([]*sarama.ProducerMessage, 0, len(events))
+	ids := make([]int64, 0, len(events))
+	for _, e := range events {
+		msgs = append(msgs, r.message(e))
+		ids = append(ids, e.ID)
+	}
+
+	if err = r.producer.SendMessages(msgs); err != nil {
+		return 0, fmt.Errorf("send events: %w", err)
+	}
+
+	if err = r.repo.MarkSent(ctx, ids); err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+func (r *Relay) cleanup(ctx context.Context) {
+	deleted, err := r.repo.DeleteSent(ctx, time.Now().Add(-r.cfg.Retention))
+	if err != nil {
+		r.log.Error("outbox cleanup failed", slog.Any("error", err))
+		return
+	}
+	r.log.Info("sent outbox events deleted", slog.Int64("count", deleted))
+}
+
+// message keys the event by order uid, so the events of one order stay in
+// one partition.
+func (r *Relay) message(e models.OutboxEvent) *sarama.ProducerMessage {
+	return &sarama.ProducerMessage{
+		Topic: r.cfg.Topic,
+		Key:   sarama.StringEncoder(e.OrderUID),
+		Value: sarama.ByteEncoder(e.Payload),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(HeaderEventID), Value: []byte(e.EventID)},
+			{Key: []byte(HeaderEventType), Value: []byte(e.Type)},
+		},
+	}
+}
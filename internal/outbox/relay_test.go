@@ -0,0 +1,202 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/config"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var (
+	ErrDB     = errors.New("db error")
+	ErrBroker = errors.New("broker down")
+)
+
+func pending(ids ...int64) []models.OutboxEvent {
+	events := make([]models.OutboxEvent, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, models.OutboxEvent{
+			ID:       id,
+			EventID:  "event-" + strconv.FormatInt(id, 10),
+			OrderUID: "uid1",
+			Type:     models.EventOrderCreated,
+			Payload:  []byte(`{"order_uid":"uid1"}`),
+		})
+	}
+	return events
+}
+
+func TestRelay_publishBatch(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		repoMock *rmocks.OutboxRepositoryMock
+		producer *mocks.SyncProducer
+	}
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, f *fields)
+		want    int
+		wantErr error
+	}{
+		{
+			name: "Published And Marked Sent",
+			prepare: func(t *testing.T, f *fields) {
+				f.repoMock.ClaimPendingMock.Expect(minimock.AnyContext, 2, time.Minute).Return(pending(1, 2), nil)
+				check := func(msg *sarama.ProducerMessage) error {
+					assert.Equal(t, "order-events", msg.Topic)
+					key, _ := msg.Key.Encode()
+					assert.Equal(t, "uid1", string(key))
+					assert.Equal(t, HeaderEventID, string(msg.Headers[0].Key))
+					assert.Equal(t, models.EventOrderCreated, string(msg.Headers[1].Value))
+					return nil
+				}
+				f.producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(check)
+				f.producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(check)
+				f.repoMock.MarkSentMock.Expect(minimock.AnyContext, []int64{1, 2}).Return(nil)
+			},
+			want: 2,
+		},
+		{
+			name: "Nothing Pending",
+			prepare: func(_ *testing.T, f *fields) {
+				f.repoMock.ClaimPendingMock.Return(nil, nil)
+			},
+		},
+		{
+			name: "Send Fails",
+			prepare: func(_ *testing.T, f *fields) {
+				f.repoMock.ClaimPendingMock.Return(pending(1), nil)
+				f.producer.ExpectSendMessageAndFail(ErrBroker)
+			},
+			wantErr: ErrBroker,
+		},
+		{
+			name: "Mark Sent Fails",
+			prepare: func(_ *testing.T, f *fields) {
+				f.repoMock.ClaimPendingMock.Return(pending(1), nil)
+				f.producer.ExpectSendMessageAndSucceed()
+				f.repoMock.MarkSentMock.Return(ErrDB)
+			},
+			wantErr: ErrDB,
+		},
+		{
+			name: "Claim Fails",
+			prepare: func(_ *testing.T, f *fields) {
+				f.repoMock.ClaimPendingMock.Return(nil, ErrDB)
+			},
+			wantErr: ErrDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOutboxRepositoryMock(ctrl)
+			producer := mocks.NewSyncProducer(t, nil)
+			t.Cleanup(func() { _ = producer.Close() })
+
+			tt.prepare(t, &fields{repoMock: repoMock, producer: producer})
+
+			cfg := config.OutboxConfig{Topic: "order-events", BatchSize: 2, ClaimTimeout: time.Minute}
+			r := newRelay(cfg, repoMock, producer, slog.Default())
+
+			got, err := r.publishBatch(context.Background())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRelay_publishPending_DrainsFullBatches(t *testing.T) {
+	t.Parallel()
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOutboxRepositoryMock(ctrl)
+	producer := mocks.NewSyncProducer(t, nil)
+	t.Cleanup(func() { _ = producer.Close() })
+
+	batches := [][]models.OutboxEvent{pending(1, 2), pending(3)}
+	repoMock.ClaimPendingMock.Set(func(context.Context, int, time.Duration) ([]models.OutboxEvent, error) {
+		batch := batches[0]
+		batches = batches[1:]
+		return batch, nil
+	})
+	for range 3 {
+		producer.ExpectSendMessageAndSucceed()
+	}
+	repoMock.MarkSentMock.Return(nil)
+
+	r := newRelay(config.OutboxConfig{BatchSize: 2}, repoMock, producer, slog.Default())
+	r.publishPending(context.Background())
+
+	assert.Empty(t, batches)
+	assert.Equal(t, uint64(2), repoMock.MarkSentAfterCounter())
+}
+
+func TestRelay_cleanup(t *testing.T) {
+	t.Parallel()
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOutboxRepositoryMock(ctrl)
+
+	before := time.Now().Add(-time.Hour)
+	repoMock.DeleteSentMock.Set(func(_ context.Context, cutoff time.Time) (int64, error) {
+		assert.WithinDuration(t, before, cutoff, time.Second)
+		return 3, nil
+	})
+
+	r := newRelay(config.OutboxConfig{Retention: time.Hour}, repoMock, nil, slog.Default())
+	r.cleanup(context.Background())
+}
+
+func TestRelay_Stop(t *testing.T) {
+	t.Parallel()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	cfg := config.OutboxConfig{PollInterval: time.Hour, CleanupInterval: time.Hour}
+	r := newRelay(cfg, nil, producer, slog.Default())
+
+	go r.Run(context.Background())
+
+	assert.NoError(t, r.Stop(context.Background()))
+}
+
+// closeProducer is a producer that records whether it was closed.
+type closeProducer struct {
+	sarama.SyncProducer
+	closed bool
+}
+
+func (p *closeProducer) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestRelay_Stop_Timeout(t *testing.T) {
+	t.Parallel()
+
+	producer := &closeProducer{}
+	r := newRelay(config.OutboxConfig{}, nil, producer, slog.Default())
+
+	// Run never ends, as if a send hung
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, r.Stop(ctx), context.Canceled)
+	assert.True(t, producer.closed)
+}
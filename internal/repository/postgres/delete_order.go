@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+)
+
+func (r *OrderRepo) SoftDeleteOrder(ctx context.Context, uid string) error {
+	query := `
+		UPDATE orders SET deleted_at = now(), version = version + 1
+		WHERE order_uid = $1 AND deleted_at IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, uid)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrOrderNotFound
+	}
+
+	return r.notifyChanged(ctx, uid)
+}
+
+// HardDeleteOrder removes the order row; deliveries, payments, items and
+// status history go with it through ON DELETE CASCADE.
+func (r *OrderRepo) HardDeleteOrder(ctx context.Context, uid string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM orders WHERE order_uid = $1`, uid)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrOrderNotFound
+	}
+
+	return r.notifyChanged(ctx, uid)
+}
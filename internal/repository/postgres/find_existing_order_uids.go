@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+)
+
+// FindExistingOrderUIDs returns the subset of uids already stored,
+// soft-deleted orders included.
+func (r *OrderRepo) FindExistingOrderUIDs(ctx context.Context, uids []string) (map[string]struct{}, error) {
+	existing := make(map[string]struct{})
+	if len(uids) == 0 {
+		return existing, nil
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT order_uid FROM orders WHERE order_uid = ANY($1)`, uids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var uid string
+		if err = rows.Scan(&uid); err != nil {
+			return nil, err
+		}
+		existing[uid] = struct{}{}
+	}
+
+	return existing, rows.Err()
+}
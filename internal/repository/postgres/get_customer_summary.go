@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+)
+
+func (r *OrderRepo) GetCustomerSummary(ctx context.Context, customerID string) (*models.CustomerSummary, error) {
+	summary := &models.CustomerSummary{
+		CustomerID: customerID,
+		TotalSpent: make(map[string]int64),
+	}
+
+	query := `
+		SELECT COUNT(*), MIN(date_created), MAX(date_created) FROM orders WHERE customer_id = $1 AND deleted_at IS NULL
+	`
+
+	err := r.db.QueryRow(ctx, query, customerID).Scan(
+		&summary.OrderCount,
+		&summary.FirstOrderAt,
+		&summary.LastOrderAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if summary.OrderCount == 0 {
+		return summary, nil
+	}
+
+	if err = r.getCustomerSpent(ctx, summary); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func (r *OrderRepo) getCustomerSpent(ctx context.Context, summary *models.CustomerSummary) error {
+	query := `
+		SELECT p.currency, SUM(p.amount)
+		FROM payments p JOIN orders o ON o.order_uid = p.order_uid
+		WHERE o.customer_id = $1 AND o.deleted_at IS NULL
+		GROUP BY p.currency
+	`
+
+	rows, err := r.db.Query(ctx, query, summary.CustomerID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var currency string
+		var total int64
+
+		if err = rows.Scan(&currency, &total); err != nil {
+			return err
+		}
+
+		summary.TotalSpent[currency] = total
+	}
+
+	return rows.Err()
+}
@@ -8,10 +8,10 @@ import (
 	"github.com/sdvaanyaa/order-service/internal/repository"
 )
 
-func (r *OrderRepo) GetOrderByUID(ctx context.Context, uid string) (*models.Order, error) {
+func (r *OrderRepo) GetOrderByUID(ctx context.Context, uid string, includeDeleted bool) (*models.Order, error) {
 	order := &models.Order{OrderUID: uid}
 
-	if err := r.getOrder(ctx, uid, order); err != nil {
+	if err := r.getOrder(ctx, uid, includeDeleted, order); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrOrderNotFound
 		}
@@ -33,14 +33,14 @@ func (r *OrderRepo) GetOrderByUID(ctx context.Context, uid string) (*models.Orde
 	return order, nil
 }
 
-func (r *OrderRepo) getOrder(ctx context.Context, uid string, order *models.Order) error {
+func (r *OrderRepo) getOrder(ctx context.Context, uid string, includeDeleted bool, order *models.Order) error {
 	query := `
 		SELECT track_number, entry, locale, internal_signature, customer_id,
-		       delivery_service, shardkey, sm_id, date_created, oof_shard
-		FROM orders WHERE order_uid = $1
+		       delivery_service, shardkey, sm_id, date_created, oof_shard, status, version, deleted_at
+		FROM orders WHERE order_uid = $1 AND ($2 OR deleted_at IS NULL)
 	`
 
-	return r.db.QueryRow(ctx, query, uid).Scan(
+	return r.db.QueryRow(ctx, query, uid, includeDeleted).Scan(
 		&order.TrackNumber,
 		&order.Entry,
 		&order.Locale,
@@ -51,6 +51,9 @@ func (r *OrderRepo) getOrder(ctx context.Context, uid string, order *models.Orde
 		&order.SmID,
 		&order.DateCreated,
 		&order.OofShard,
+		&order.Status,
+		&order.Version,
+		&order.DeletedAt,
 	)
 }
 
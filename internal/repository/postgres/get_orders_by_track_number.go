@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+)
+
+func (r *OrderRepo) GetOrdersByTrackNumber(ctx context.Context, trackNumber string) ([]*models.Order, error) {
+	query := `
+		SELECT ` + orderColumns + `
+		FROM orders
+		WHERE deleted_at IS NULL
+		  AND (track_number = $1 OR order_uid IN (SELECT order_uid FROM items WHERE track_number = $1))
+		ORDER BY date_created DESC, order_uid DESC
+	`
+
+	orders, err := r.queryOrders(ctx, query, trackNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.loadDetails(ctx, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
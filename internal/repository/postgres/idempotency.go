@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+	"log/slog"
+	"time"
+)
+
+const reserveAttempts = 3
+
+type IdempotencyRepo struct {
+	db  *pgdb.Client
+	log *slog.Logger
+}
+
+func NewIdempotencyRepo(db *pgdb.Client, log *slog.Logger) repository.IdempotencyRepository {
+	return &IdempotencyRepo{
+		db:  db,
+		log: log,
+	}
+}
+
+// ReserveKey retries when the holder releases the key between the failed
+// insert and the lookup. If that keeps happening the key is reported as in
+// progress, which it was every time.
+func (r *IdempotencyRepo) ReserveKey(
+	ctx context.Context,
+	key, requestHash string,
+	ttl time.Duration,
+) (*models.IdempotencyRecord, bool, error) {
+	for range reserveAttempts {
+		reserved, err := r.insertKey(ctx, key, requestHash, ttl)
+		if err != nil || reserved {
+			return nil, reserved, err
+		}
+
+		existing, err := r.getKey(ctx, key)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		return existing, false, nil
+	}
+
+	return &models.IdempotencyRecord{Key: key, RequestHash: requestHash}, false, nil
+}
+
+func (r *IdempotencyRepo) insertKey(ctx context.Context, key, requestHash string, ttl time.Duration) (bool, error) {
+	// an expired key is taken over as if it never existed
+	query := `
+		INSERT INTO idempotency_keys (key, request_hash, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+		    status_code = NULL,
+		    response_body = NULL,
+		    created_at = now(),
+		    expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < now()
+		RETURNING key
+	`
+
+	var reservedKey string
+	err := r.db.QueryRow(ctx, query, key, requestHash, time.Now().Add(ttl)).Scan(&reservedKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (r *IdempotencyRepo) getKey(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	query := `
+		SELECT key, request_hash, COALESCE(status_code, 0), response_body
+		FROM idempotency_keys WHERE key = $1
+	`
+
+	var record models.IdempotencyRecord
+	err := r.db.QueryRow(ctx, query, key).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.Body,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *IdempotencyRepo) CompleteKey(ctx context.Context, key string, statusCode int, body []byte) error {
+	query := `
+		UPDATE idempotency_keys SET status_code = $2, response_body = $3 WHERE key = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, key, statusCode, body)
+
+	return err
+}
+
+func (r *IdempotencyRepo) ReleaseKey(ctx context.Context, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND status_code IS NULL`, key)
+
+	return err
+}
+
+func (r *IdempotencyRepo) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < now()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"strings"
+)
+
+func (r *OrderRepo) ListOrders(
+	ctx context.Context,
+	filter models.OrderFilter,
+	after *models.OrderCursor,
+	limit int,
+) ([]*models.Order, error) {
+	where, args := buildOrderFilter(filter, after)
+
+	args = append(args, limit)
+	query := fmt.Sprintf(
+		`SELECT %s FROM orders %s ORDER BY date_created DESC, order_uid DESC LIMIT $%d`,
+		orderColumns, where, len(args),
+	)
+
+	orders, err := r.queryOrders(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.loadDetails(ctx, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func buildOrderFilter(filter models.OrderFilter, after *models.OrderCursor) (string, []any) {
+	conds := []string{"deleted_at IS NULL"}
+	var args []any
+
+	add := func(cond string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			placeholders[i] = len(args)
+		}
+		conds = append(conds, fmt.Sprintf(cond, placeholders...))
+	}
+
+	if filter.CustomerID != "" {
+		add("customer_id = $%d", filter.CustomerID)
+	}
+	if filter.DeliveryService != "" {
+		add("delivery_service = $%d", filter.DeliveryService)
+	}
+	if filter.Locale != "" {
+		add("locale = $%d", filter.Locale)
+	}
+	if !filter.DateFrom.IsZero() {
+		add("date_created >= $%d", filter.DateFrom)
+	}
+	if !filter.DateTo.IsZero() {
+		add("date_created < $%d", filter.DateTo)
+	}
+	if after != nil {
+		add("(date_created, order_uid) < ($%d, $%d)", after.DateCreated, after.OrderUID)
+	}
+
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
@@ -3,32 +3,51 @@ package postgres
 import (
 	"context"
 	"github.com/sdvaanyaa/order-service/internal/models"
+	"time"
 )
 
-func (r *OrderRepo) LoadAllOrders(ctx context.Context) (map[string]*models.Order, error) {
-	cache := make(map[string]*models.Order)
-
-	rows, err := r.db.Query(ctx, `SELECT order_uid FROM orders`)
+// LoadAllOrders loads a page of the selected orders, newest first. The page
+// is selected once and its details are loaded by uid, so orders written
+// meanwhile cannot shift the page between the queries.
+func (r *OrderRepo) LoadAllOrders(ctx context.Context, opts models.LoadOptions) ([]*models.Order, error) {
+	query := `
+		SELECT ` + orderColumns + ` FROM orders
+		WHERE ($1 OR deleted_at IS NULL)
+			AND ($2::timestamptz IS NULL OR date_created >= $2)
+			AND ($3::timestamptz IS NULL OR (date_created, order_uid) < ($3, $4))
+		ORDER BY date_created DESC, order_uid DESC
+		LIMIT $5
+	`
+
+	orders, err := r.queryOrders(ctx, query, loadArgs(opts)...)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var uid string
+	if err = r.loadDetails(ctx, orders); err != nil {
+		return nil, err
+	}
 
-		err = rows.Scan(&uid)
-		if err != nil {
-			return nil, err
-		}
+	return orders, nil
+}
 
-		order, err := r.GetOrderByUID(ctx, uid)
-		if err != nil {
-			return nil, err
-		}
+func loadArgs(opts models.LoadOptions) []any {
+	var since *time.Time
+	if !opts.Since.IsZero() {
+		since = &opts.Since
+	}
+
+	var afterDate *time.Time
+	var afterUID *string
+	if opts.After != nil {
+		afterDate = &opts.After.DateCreated
+		afterUID = &opts.After.OrderUID
+	}
 
-		cache[uid] = order
+	var limit *int
+	if opts.Limit > 0 {
+		limit = &opts.Limit
 	}
 
-	return cache, nil
+	return []any{opts.IncludeDeleted, since, afterDate, afterUID, limit}
 }
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+)
+
+const orderColumns = `
+	order_uid, track_number, entry, locale, internal_signature, customer_id,
+	delivery_service, shardkey, sm_id, date_created, oof_shard, status, version, deleted_at
+`
+
+func scanOrder(row interface{ Scan(dest ...any) error }) (*models.Order, error) {
+	var order models.Order
+
+	err := row.Scan(
+		&order.OrderUID,
+		&order.TrackNumber,
+		&order.Entry,
+		&order.Locale,
+		&order.InternalSignature,
+		&order.CustomerID,
+		&order.DeliveryService,
+		&order.Shardkey,
+		&order.SmID,
+		&order.DateCreated,
+		&order.OofShard,
+		&order.Status,
+		&order.Version,
+		&order.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (r *OrderRepo) queryOrders(ctx context.Context, query string, args ...any) ([]*models.Order, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*models.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// loadDetails fills deliveries, payments and items of the given orders
+// with one query per table instead of one per order.
+func (r *OrderRepo) loadDetails(ctx context.Context, orders []*models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byUID := make(map[string]*models.Order, len(orders))
+	uids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byUID[order.OrderUID] = order
+		uids = append(uids, order.OrderUID)
+	}
+
+	if err := r.fillDeliveries(ctx, byUID, uids); err != nil {
+		return err
+	}
+
+	if err := r.fillPayments(ctx, byUID, uids); err != nil {
+		return err
+	}
+
+	return r.fillItems(ctx, byUID, uids)
+}
+
+func (r *OrderRepo) fillDeliveries(ctx context.Context, byUID map[string]*models.Order, uids []string) error {
+	query := `
+		SELECT order_uid, name, phone, zip, city, address, region, email
+		FROM deliveries WHERE order_uid = ANY($1)
+	`
+
+	rows, err := r.db.Query(ctx, query, uids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var uid string
+		var d models.Delivery
+
+		err = rows.Scan(&uid, &d.Name, &d.Phone, &d.Zip, &d.City, &d.Address, &d.Region, &d.Email)
+		if err != nil {
+			return err
+		}
+
+		if order, ok := byUID[uid]; ok {
+			order.Delivery = d
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *OrderRepo) fillPayments(ctx context.Context, byUID map[string]*models.Order, uids []string) error {
+	query := `
+		SELECT order_uid, transaction, request_id, currency, provider, amount,
+		       payment_dt, bank, delivery_cost, goods_total, custom_fee
+		FROM payments WHERE order_uid = ANY($1)
+	`
+
+	rows, err := r.db.Query(ctx, query, uids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var uid string
+		var p models.Payment
+
+		err = rows.Scan(
+			&uid,
+			&p.Transaction,
+			&p.RequestID,
+			&p.Currency,
+			&p.Provider,
+			&p.Amount,
+			&p.PaymentDt,
+			&p.Bank,
+			&p.DeliveryCost,
+			&p.GoodsTotal,
+			&p.CustomFee,
+		)
+		if err != nil {
+			return err
+		}
+
+		if order, ok := byUID[uid]; ok {
+			order.Payment = p
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *OrderRepo) fillItems(ctx context.Context, byUID map[string]*models.Order, uids []string) error {
+	query := `
+		SELECT order_uid, chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status
+		FROM items WHERE order_uid = ANY($1) ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, uids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var uid string
+		var item models.Item
+
+		err = rows.Scan(
+			&uid,
+			&item.ChrtID,
+			&item.TrackNumber,
+			&item.Price,
+			&item.Rid,
+			&item.Name,
+			&item.Sale,
+			&item.Size,
+			&item.TotalPrice,
+			&item.NmID,
+			&item.Brand,
+			&item.Status,
+		)
+		if err != nil {
+			return err
+		}
+
+		if order, ok := byUID[uid]; ok {
+			order.Items = append(order.Items, item)
+		}
+	}
+
+	return rows.Err()
+}
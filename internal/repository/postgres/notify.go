@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+)
+
+// notifyChangedQuery sends a repository.OrderChange with the version the
+// order has at this point of the transaction.
+const notifyChangedQuery = `
+	SELECT pg_notify($1, json_build_object(
+		'uid', $2::text,
+		'version', (SELECT version FROM orders WHERE order_uid = $2)
+	)::text)
+`
+
+// notifyChanged tells listening replicas that the order was written. Inside a
+// transaction the notification is only delivered on commit.
+func (r *OrderRepo) notifyChanged(ctx context.Context, uid string) error {
+	_, err := r.db.Exec(ctx, notifyChangedQuery, repository.OrderChangedChannel, uid)
+
+	return err
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+type OutboxRepo struct {
+	db  *pgdb.Client
+	log *slog.Logger
+}
+
+func NewOutboxRepo(db *pgdb.Client, log *slog.Logger) repository.OutboxRepository {
+	return &OutboxRepo{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *OutboxRepo) AddEvents(ctx context.Context, events []models.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox (event_id, order_uid, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		batch.Queue(query, e.EventID, e.OrderUID, e.Type, e.Payload)
+	}
+
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+// ClaimPending claims the events in one statement, so no transaction stays
+// open while they are published.
+func (r *OutboxRepo) ClaimPending(
+	ctx context.Context,
+	limit int,
+	claimFor time.Duration,
+) ([]models.OutboxEvent, error) {
+	query := `
+		UPDATE outbox SET claimed_until = now() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE sent_at IS NULL AND (claimed_until IS NULL OR claimed_until < now())
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_id, order_uid, event_type, payload, created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, limit, claimFor.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var e models.OutboxEvent
+		if err = rows.Scan(&e.ID, &e.EventID, &e.OrderUID, &e.Type, &e.Payload, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery
+	slices.SortFunc(events, func(a, b models.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+
+	return events, nil
+}
+
+func (r *OutboxRepo) MarkSent(ctx context.Context, ids []int64) error {
+	query := `UPDATE outbox SET sent_at = now() WHERE id = ANY($1)`
+
+	_, err := r.db.Exec(ctx, query, ids)
+
+	return err
+}
+
+func (r *OutboxRepo) DeleteSent(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM outbox WHERE sent_at < $1`
+
+	tag, err := r.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -3,6 +3,30 @@ package postgres
 import (
 	"context"
 	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+)
+
+const (
+	insertOrderQuery = `
+		INSERT INTO orders (order_uid, track_number, entry, locale, internal_signature,
+		                    customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	insertDeliveryQuery = `
+		INSERT INTO deliveries (order_uid, name, phone, zip, city, address, region, email)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	insertPaymentQuery = `
+		INSERT INTO payments (transaction, order_uid, request_id, currency, provider,
+		                      amount, payment_dt, bank, delivery_cost, goods_total, custom_fee)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	insertItemQuery = `
+		INSERT INTO items (order_uid, chrt_id, track_number, price,
+		                   rid, name, sale, size, total_price, nm_id, brand, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
 )
 
 func (r *OrderRepo) SaveOrder(ctx context.Context, order *models.Order) error {
@@ -22,19 +46,49 @@ func (r *OrderRepo) SaveOrder(ctx context.Context, order *models.Order) error {
 		return err
 	}
 
-	return nil
+	if err := r.insertStatusHistory(ctx, order.OrderUID, "", order.Status); err != nil {
+		return err
+	}
+
+	return r.notifyChanged(ctx, order.OrderUID)
 }
 
 func (r *OrderRepo) insertOrder(ctx context.Context, order *models.Order) error {
-	query := `
-		INSERT INTO orders (order_uid, track_number, entry, locale, internal_signature,
-		                    customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-	`
+	_, err := r.db.Exec(ctx, insertOrderQuery, orderArgs(order)...)
+	if pgdb.IsUniqueViolation(err) {
+		return repository.ErrOrderExists
+	}
+
+	return err
+}
+
+func (r *OrderRepo) insertDelivery(ctx context.Context, order *models.Order) error {
+	_, err := r.db.Exec(ctx, insertDeliveryQuery, deliveryArgs(order)...)
+
+	return err
+}
+
+func (r *OrderRepo) insertPayment(ctx context.Context, order *models.Order) error {
+	_, err := r.db.Exec(ctx, insertPaymentQuery, paymentArgs(order)...)
+	if pgdb.IsUniqueViolation(err) {
+		return repository.ErrDuplicateTransaction
+	}
+
+	return err
+}
+
+func (r *OrderRepo) insertItems(ctx context.Context, order *models.Order) error {
+	for _, item := range order.Items {
+		if _, err := r.db.Exec(ctx, insertItemQuery, itemArgs(order.OrderUID, item)...); err != nil {
+			return err
+		}
+	}
 
-	_, err := r.db.Exec(
-		ctx,
-		query,
+	return nil
+}
+
+func orderArgs(order *models.Order) []any {
+	return []any{
 		order.OrderUID,
 		order.TrackNumber,
 		order.Entry,
@@ -46,20 +100,12 @@ func (r *OrderRepo) insertOrder(ctx context.Context, order *models.Order) error
 		order.SmID,
 		order.DateCreated,
 		order.OofShard,
-	)
-
-	return err
+		order.Status,
+	}
 }
 
-func (r *OrderRepo) insertDelivery(ctx context.Context, order *models.Order) error {
-	query := `
-		INSERT INTO deliveries (order_uid, name, phone, zip, city, address, region, email)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`
-
-	_, err := r.db.Exec(
-		ctx,
-		query,
+func deliveryArgs(order *models.Order) []any {
+	return []any{
 		order.OrderUID,
 		order.Delivery.Name,
 		order.Delivery.Phone,
@@ -68,21 +114,11 @@ func (r *OrderRepo) insertDelivery(ctx context.Context, order *models.Order) err
 		order.Delivery.Address,
 		order.Delivery.Region,
 		order.Delivery.Email,
-	)
-
-	return err
+	}
 }
 
-func (r *OrderRepo) insertPayment(ctx context.Context, order *models.Order) error {
-	query := `
-		INSERT INTO payments (transaction, order_uid, request_id, currency, provider,
-		                      amount, payment_dt, bank, delivery_cost, goods_total, custom_fee)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-	`
-
-	_, err := r.db.Exec(
-		ctx,
-		query,
+func paymentArgs(order *models.Order) []any {
+	return []any{
 		order.Payment.Transaction,
 		order.OrderUID,
 		order.Payment.RequestID,
@@ -94,40 +130,22 @@ func (r *OrderRepo) insertPayment(ctx context.Context, order *models.Order) erro
 		order.Payment.DeliveryCost,
 		order.Payment.GoodsTotal,
 		order.Payment.CustomFee,
-	)
-
-	return err
+	}
 }
 
-func (r *OrderRepo) insertItems(ctx context.Context, order *models.Order) error {
-	query := `
-		INSERT INTO items (order_uid, chrt_id, track_number, price,
-		                   rid, name, sale, size, total_price, nm_id, brand, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-	`
-
-	for _, item := range order.Items {
-		_, err := r.db.Exec(
-			ctx,
-			query,
-			order.OrderUID,
-			item.ChrtID,
-			item.TrackNumber,
-			item.Price,
-			item.Rid,
-			item.Name,
-			item.Sale,
-			item.Size,
-			item.TotalPrice,
-			item.NmID,
-			item.Brand,
-			item.Status,
-		)
-
-		if err != nil {
-			return err
-		}
+func itemArgs(uid string, item models.Item) []any {
+	return []any{
+		uid,
+		item.ChrtID,
+		item.TrackNumber,
+		item.Price,
+		item.Rid,
+		item.Name,
+		item.Sale,
+		item.Size,
+		item.TotalPrice,
+		item.NmID,
+		item.Brand,
+		item.Status,
 	}
-
-	return nil
 }
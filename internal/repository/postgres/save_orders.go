@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+)
+
+var itemColumns = []string{
+	"order_uid", "chrt_id", "track_number", "price",
+	"rid", "name", "sale", "size", "total_price", "nm_id", "brand", "status",
+}
+
+// SaveOrders stores several new orders in two round trips: one batch for the
+// per-order rows and one COPY for all items. It should run inside a
+// transaction, since a failure leaves the batch half applied otherwise. The
+// first failing statement decides the error, so a duplicate anywhere in the
+// batch is reported the same way SaveOrder reports it.
+func (r *OrderRepo) SaveOrders(ctx context.Context, orders []*models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	var onUnique []error
+
+	queue := func(uniqueErr error, query string, args ...any) {
+		batch.Queue(query, args...)
+		onUnique = append(onUnique, uniqueErr)
+	}
+
+	for _, order := range orders {
+		queue(repository.ErrOrderExists, insertOrderQuery, orderArgs(order)...)
+		queue(nil, insertDeliveryQuery, deliveryArgs(order)...)
+		queue(repository.ErrDuplicateTransaction, insertPaymentQuery, paymentArgs(order)...)
+		queue(nil, insertStatusHistoryQuery, order.OrderUID, "", order.Status)
+		queue(nil, notifyChangedQuery, repository.OrderChangedChannel, order.OrderUID)
+	}
+
+	if err := r.execBatch(ctx, batch, onUnique); err != nil {
+		return err
+	}
+
+	_, err := r.db.CopyFrom(ctx, pgx.Identifier{"items"}, itemColumns, pgx.CopyFromRows(itemRows(orders)))
+	if err != nil {
+		return fmt.Errorf("copy items: %w", err)
+	}
+
+	return nil
+}
+
+// execBatch reads every result of the batch and maps a unique violation of
+// the i-th statement to onUnique[i] when it is set.
+func (r *OrderRepo) execBatch(ctx context.Context, batch *pgx.Batch, onUnique []error) (err error) {
+	results := r.db.SendBatch(ctx, batch)
+	defer func() {
+		// Close repeats the first statement error, which is already in err
+		if closeErr := results.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	for _, uniqueErr := range onUnique {
+		_, err = results.Exec()
+		if err == nil {
+			continue
+		}
+
+		if pgdb.IsUniqueViolation(err) && uniqueErr != nil {
+			return uniqueErr
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func itemRows(orders []*models.Order) [][]any {
+	var rows [][]any
+	for _, order := range orders {
+		for _, item := range order.Items {
+			rows = append(rows, itemArgs(order.OrderUID, item))
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+)
+
+// UpdateOrder overwrites delivery, payment and items of an existing order.
+// The write only happens if the stored version still equals version,
+// otherwise ErrVersionConflict is returned. Must run inside a transaction.
+func (r *OrderRepo) UpdateOrder(ctx context.Context, order *models.Order, version int) error {
+	if err := r.bumpVersion(ctx, order.OrderUID, version); err != nil {
+		return err
+	}
+
+	if err := r.updateDelivery(ctx, order); err != nil {
+		return err
+	}
+
+	if err := r.updatePayment(ctx, order); err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, `DELETE FROM items WHERE order_uid = $1`, order.OrderUID); err != nil {
+		return err
+	}
+
+	if err := r.insertItems(ctx, order); err != nil {
+		return err
+	}
+
+	return r.notifyChanged(ctx, order.OrderUID)
+}
+
+func (r *OrderRepo) bumpVersion(ctx context.Context, uid string, version int) error {
+	query := `
+		UPDATE orders SET version = version + 1 WHERE order_uid = $1 AND version = $2 AND deleted_at IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, uid, version)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrVersionConflict
+	}
+
+	return nil
+}
+
+func (r *OrderRepo) updateDelivery(ctx context.Context, order *models.Order) error {
+	query := `
+		UPDATE deliveries
+		SET name = $2, phone = $3, zip = $4, city = $5, address = $6, region = $7, email = $8
+		WHERE order_uid = $1
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		order.OrderUID,
+		order.Delivery.Name,
+		order.Delivery.Phone,
+		order.Delivery.Zip,
+		order.Delivery.City,
+		order.Delivery.Address,
+		order.Delivery.Region,
+		order.Delivery.Email,
+	)
+
+	return err
+}
+
+func (r *OrderRepo) updatePayment(ctx context.Context, order *models.Order) error {
+	query := `
+		UPDATE payments
+		SET transaction = $2, request_id = $3, currency = $4, provider = $5, amount = $6,
+		    payment_dt = $7, bank = $8, delivery_cost = $9, goods_total = $10, custom_fee = $11
+		WHERE order_uid = $1
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		order.OrderUID,
+		order.Payment.Transaction,
+		order.Payment.RequestID,
+		order.Payment.Currency,
+		order.Payment.Provider,
+		order.Payment.Amount,
+		order.Payment.PaymentDt,
+		order.Payment.Bank,
+		order.Payment.DeliveryCost,
+		order.Payment.GoodsTotal,
+		order.Payment.CustomFee,
+	)
+	if pgdb.IsUniqueViolation(err) {
+		return repository.ErrDuplicateTransaction
+	}
+
+	return err
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+)
+
+// UpdateOrderStatus moves the order from one status to another. The current
+// status is checked in the same statement, so a concurrent change is reported
+// as ErrStatusConflict instead of being overwritten.
+func (r *OrderRepo) UpdateOrderStatus(ctx context.Context, uid string, from, to models.OrderStatus) error {
+	query := `
+		UPDATE orders SET status = $3, version = version + 1 WHERE order_uid = $1 AND status = $2 AND deleted_at IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, uid, from, to)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrStatusConflict
+	}
+
+	if err = r.insertStatusHistory(ctx, uid, from, to); err != nil {
+		return err
+	}
+
+	return r.notifyChanged(ctx, uid)
+}
+
+const insertStatusHistoryQuery = `
+	INSERT INTO order_status_history (order_uid, from_status, to_status)
+	VALUES ($1, NULLIF($2, ''), $3)
+`
+
+func (r *OrderRepo) insertStatusHistory(ctx context.Context, uid string, from, to models.OrderStatus) error {
+	_, err := r.db.Exec(ctx, insertStatusHistoryQuery, uid, from, to)
+
+	return err
+}
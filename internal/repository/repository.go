@@ -4,14 +4,67 @@ import (
 	"context"
 	"errors"
 	"github.com/sdvaanyaa/order-service/internal/models"
+	"time"
 )
 
+// OrderChangedChannel is notified with an OrderChange on every write, so
+// other replicas can drop their cached copy.
+const OrderChangedChannel = "order_changed"
+
+// OrderChange is the JSON payload sent on OrderChangedChannel. Version is the
+// order version after the write, or zero once the order is deleted for good.
+type OrderChange struct {
+	UID     string `json:"uid"`
+	Version int    `json:"version"`
+}
+
 var (
-	ErrOrderNotFound = errors.New("timestamp not found")
+	ErrOrderNotFound        = errors.New("timestamp not found")
+	ErrStatusConflict       = errors.New("order status changed concurrently")
+	ErrVersionConflict      = errors.New("order version mismatch")
+	ErrOrderExists          = errors.New("order uid already stored")
+	ErrDuplicateTransaction = errors.New("payment transaction already stored")
 )
 
 type OrderRepository interface {
 	SaveOrder(ctx context.Context, order *models.Order) error
-	GetOrderByUID(ctx context.Context, uid string) (*models.Order, error)
-	LoadAllOrders(ctx context.Context) (map[string]*models.Order, error)
+	SaveOrders(ctx context.Context, orders []*models.Order) error
+	GetOrderByUID(ctx context.Context, uid string, includeDeleted bool) (*models.Order, error)
+	LoadAllOrders(ctx context.Context, opts models.LoadOptions) ([]*models.Order, error)
+	ListOrders(
+		ctx context.Context,
+		filter models.OrderFilter,
+		after *models.OrderCursor,
+		limit int,
+	) ([]*models.Order, error)
+	GetOrdersByTrackNumber(ctx context.Context, trackNumber string) ([]*models.Order, error)
+	GetCustomerSummary(ctx context.Context, customerID string) (*models.CustomerSummary, error)
+	UpdateOrderStatus(ctx context.Context, uid string, from, to models.OrderStatus) error
+	UpdateOrder(ctx context.Context, order *models.Order, version int) error
+	SoftDeleteOrder(ctx context.Context, uid string) error
+	HardDeleteOrder(ctx context.Context, uid string) error
+	FindExistingOrderUIDs(ctx context.Context, uids []string) (map[string]struct{}, error)
+}
+
+type OutboxRepository interface {
+	AddEvents(ctx context.Context, events []models.OutboxEvent) error
+	// ClaimPending returns up to limit unsent events, oldest first, and
+	// claims them for claimFor. Events claimed by another relay are skipped
+	// until their claim runs out.
+	ClaimPending(ctx context.Context, limit int, claimFor time.Duration) ([]models.OutboxEvent, error)
+	MarkSent(ctx context.Context, ids []int64) error
+	DeleteSent(ctx context.Context, before time.Time) (int64, error)
+}
+
+type IdempotencyRepository interface {
+	// ReserveKey claims key for a new request. If the key is already taken
+	// and not expired, the stored record is returned with reserved == false.
+	ReserveKey(
+		ctx context.Context,
+		key, requestHash string,
+		ttl time.Duration,
+	) (existing *models.IdempotencyRecord, reserved bool, err error)
+	CompleteKey(ctx context.Context, key string, statusCode int, body []byte) error
+	ReleaseKey(ctx context.Context, key string) error
+	DeleteExpiredKeys(ctx context.Context) (int64, error)
 }
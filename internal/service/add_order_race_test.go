@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	tmocks "github.com/sdvaanyaa/order-service/pkg/pgdb/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// uniqueStore enforces the orders primary key and the unique payment
+// transaction the way Postgres does, reporting violations with the errors
+// SaveOrder maps them to.
+type uniqueStore struct {
+	mu           sync.Mutex
+	uids         map[string]struct{}
+	transactions map[string]struct{}
+}
+
+func (s *uniqueStore) save(_ context.Context, o *models.Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.uids[o.OrderUID]; ok {
+		return repository.ErrOrderExists
+	}
+	if _, ok := s.transactions[o.Payment.Transaction]; ok {
+		return repository.ErrDuplicateTransaction
+	}
+	s.uids[o.OrderUID] = struct{}{}
+	s.transactions[o.Payment.Transaction] = struct{}{}
+
+	return nil
+}
+
+func newRaceService(t *testing.T) *orderService {
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+	outboxMock := rmocks.NewOutboxRepositoryMock(ctrl)
+	transactorMock := tmocks.NewTransactorMock(ctrl)
+
+	store := &uniqueStore{uids: make(map[string]struct{}), transactions: make(map[string]struct{})}
+
+	transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	repoMock.SaveOrderMock.Set(store.save)
+	outboxMock.AddEventsMock.Optional().Return(nil)
+
+	return &orderService{
+		repo:       repoMock,
+		outbox:     outboxMock,
+		transactor: transactorMock,
+		log:        slog.Default(),
+		cache:      newTestCache(),
+		val:        validator.New(),
+	}
+}
+
+// submitConcurrently starts every AddOrder call at once and returns their
+// errors.
+func submitConcurrently(s *orderService, orders []*models.Order) []error {
+	errs := make([]error, len(orders))
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i, order := range orders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = s.AddOrder(context.Background(), order)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+// Test_orderService_AddOrder_ConcurrentDuplicates submits the same order from
+// many goroutines. Exactly one submission must win and every other one must
+// see ErrOrderAlreadyExists.
+func Test_orderService_AddOrder_ConcurrentDuplicates(t *testing.T) {
+	t.Parallel()
+
+	const submissions = 50
+
+	s := newRaceService(t)
+
+	orders := make([]*models.Order, submissions)
+	for i := range orders {
+		orders[i] = validOrder("uid1")
+	}
+
+	created := 0
+	for _, err := range submitConcurrently(s, orders) {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrOrderAlreadyExists)
+	}
+
+	assert.Equal(t, 1, created)
+}
+
+// Test_orderService_AddOrder_ConcurrentTransactionReuse submits two orders
+// sharing one payment transaction at once. One must win and the other must
+// see ErrTransactionUsed, not a duplicate of itself.
+func Test_orderService_AddOrder_ConcurrentTransactionReuse(t *testing.T) {
+	t.Parallel()
+
+	s := newRaceService(t)
+
+	first, second := validOrder("uid1"), validOrder("uid2")
+	second.Payment.Transaction = first.Payment.Transaction
+
+	created := 0
+	for _, err := range submitConcurrently(s, []*models.Order{first, second}) {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrTransactionUsed)
+		assert.NotErrorIs(t, err, ErrOrderAlreadyExists)
+	}
+
+	assert.Equal(t, 1, created)
+}
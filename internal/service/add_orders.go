@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+)
+
+const BatchChunkSize = 100
+
+// AddOrders validates and stores a batch of orders. Valid orders are checked
+// for existence with a single query and saved in chunks, one transaction per
+// chunk. If a chunk fails, its orders are retried one by one so a single bad
+// order only fails itself.
+func (s *orderService) AddOrders(ctx context.Context, orders []*models.Order) ([]models.BatchResult, error) {
+	results := make([]models.BatchResult, len(orders))
+	pending := s.validateBatch(orders, results)
+
+	uids := make([]string, 0, len(pending))
+	for _, i := range pending {
+		uids = append(uids, orders[i].OrderUID)
+	}
+
+	existing, err := s.repo.FindExistingOrderUIDs(ctx, uids)
+	if err != nil {
+		return nil, err
+	}
+
+	toSave := make([]int, 0, len(pending))
+	for _, i := range pending {
+		if _, ok := existing[orders[i].OrderUID]; ok {
+			results[i].Status = models.BatchDuplicate
+			continue
+		}
+		toSave = append(toSave, i)
+	}
+
+	for start := 0; start < len(toSave); start += BatchChunkSize {
+		end := min(start+BatchChunkSize, len(toSave))
+		s.saveChunk(ctx, orders, toSave[start:end], results)
+	}
+
+	return results, nil
+}
+
+// validateBatch fills results for invalid and repeated orders and returns
+// indexes of the ones worth saving.
+func (s *orderService) validateBatch(orders []*models.Order, results []models.BatchResult) []int {
+	pending := make([]int, 0, len(orders))
+	seen := make(map[string]struct{}, len(orders))
+
+	for i, order := range orders {
+		results[i] = models.BatchResult{Index: i, OrderUID: order.OrderUID}
+
+		if err := s.validate(order); err != nil {
+			results[i].Status = models.BatchInvalid
+			results[i].Errors = validationMessages(err)
+			continue
+		}
+
+		if _, ok := seen[order.OrderUID]; ok {
+			results[i].Status = models.BatchDuplicate
+			continue
+		}
+		seen[order.OrderUID] = struct{}{}
+
+		order.Status = models.StatusCreated
+		order.Version = 1
+		pending = append(pending, i)
+	}
+
+	return pending
+}
+
+func (s *orderService) saveChunk(ctx context.Context, orders []*models.Order, chunk []int, results []models.BatchResult) {
+	batch := make([]*models.Order, 0, len(chunk))
+	for _, i := range chunk {
+		batch = append(batch, orders[i])
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.repo.SaveOrders(txCtx, batch); err != nil {
+			return err
+		}
+		if err := s.recordEvents(txCtx, models.EventOrderCreated, batch...); err != nil {
+			return err
+		}
+		pgdb.AfterCommit(txCtx, func() { s.cacheOrders(orders, chunk) })
+		return nil
+	})
+	if err == nil {
+		for _, i := range chunk {
+			results[i].Status = models.BatchCreated
+		}
+		return
+	}
+
+	s.log.Warn("batch chunk failed, saving orders one by one", "size", len(chunk), "err", err)
+
+	for _, i := range chunk {
+		err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+			if err := s.repo.SaveOrder(txCtx, orders[i]); err != nil {
+				return err
+			}
+			if err := s.recordEvents(txCtx, models.EventOrderCreated, orders[i]); err != nil {
+				return err
+			}
+			pgdb.AfterCommit(txCtx, func() { s.cacheOrder(orders[i]) })
+			return nil
+		})
+		err = mapDuplicate(err)
+		if errors.Is(err, ErrOrderAlreadyExists) {
+			results[i].Status = models.BatchDuplicate
+			continue
+		}
+		if err != nil {
+			results[i].Status = models.BatchFailed
+			results[i].Errors = []string{err.Error()}
+			continue
+		}
+		results[i].Status = models.BatchCreated
+	}
+}
+
+func (s *orderService) cacheOrders(orders []*models.Order, indexes []int) {
+	for _, i := range indexes {
+		s.cacheOrder(orders[i])
+	}
+}
+
+func validationMessages(err error) []string {
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return []string{err.Error()}
+	}
+
+	messages := make([]string, 0, len(verr.Fields))
+	for _, f := range verr.Fields {
+		messages = append(messages, f.String())
+	}
+
+	return messages
+}
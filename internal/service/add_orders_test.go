@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	tmocks "github.com/sdvaanyaa/order-service/pkg/pgdb/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func Test_orderService_AddOrders(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		repoMock       *rmocks.OrderRepositoryMock
+		outboxMock     *rmocks.OutboxRepositoryMock
+		transactorMock *tmocks.TransactorMock
+	}
+	tests := []struct {
+		name        string
+		orders      func() []*models.Order
+		prepare     func(f *fields)
+		want        []models.BatchStatus
+		wantErr     error
+		wantCreated []string
+	}{
+		{
+			name: "Mixed Batch",
+			orders: func() []*models.Order {
+				return []*models.Order{validOrder("uid1"), {OrderUID: "bad"}, validOrder("uid1"), validOrder("uid2")}
+			},
+			prepare: func(f *fields) {
+				f.repoMock.FindExistingOrderUIDsMock.Set(func(_ context.Context, uids []string) (map[string]struct{}, error) {
+					assert.Equal(t, []string{"uid1", "uid2"}, uids)
+					return map[string]struct{}{"uid2": {}}, nil
+				})
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.SaveOrdersMock.Set(func(_ context.Context, orders []*models.Order) error {
+					assert.Len(t, orders, 1)
+					assert.Equal(t, "uid1", orders[0].OrderUID)
+					return nil
+				})
+				f.outboxMock.AddEventsMock.Set(func(_ context.Context, events []models.OutboxEvent) error {
+					assert.Len(t, events, 1)
+					assert.Equal(t, models.EventOrderCreated, events[0].Type)
+					assert.Equal(t, "uid1", events[0].OrderUID)
+					return nil
+				})
+			},
+			want: []models.BatchStatus{
+				models.BatchCreated, models.BatchInvalid, models.BatchDuplicate, models.BatchDuplicate,
+			},
+			wantCreated: []string{"uid1"},
+		},
+		{
+			name: "Chunk Failure Falls Back To Single Saves",
+			orders: func() []*models.Order {
+				return []*models.Order{validOrder("uid1"), validOrder("uid2"), validOrder("uid3")}
+			},
+			prepare: func(f *fields) {
+				f.repoMock.FindExistingOrderUIDsMock.Return(map[string]struct{}{}, nil)
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.SaveOrdersMock.Return(ErrDB)
+				f.outboxMock.AddEventsMock.Return(nil)
+				f.repoMock.SaveOrderMock.Set(func(_ context.Context, o *models.Order) error {
+					switch o.OrderUID {
+					case "uid2":
+						return ErrDB
+					case "uid3":
+						// another order's payment transaction is not a replay
+						return repository.ErrDuplicateTransaction
+					}
+					return nil
+				})
+			},
+			want:        []models.BatchStatus{models.BatchCreated, models.BatchFailed, models.BatchFailed},
+			wantCreated: []string{"uid1"},
+		},
+		{
+			name: "Existence Check Error",
+			orders: func() []*models.Order {
+				return []*models.Order{validOrder("uid1")}
+			},
+			prepare: func(f *fields) {
+				f.repoMock.FindExistingOrderUIDsMock.Return(nil, ErrDB)
+			},
+			wantErr: ErrDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+			outboxMock := rmocks.NewOutboxRepositoryMock(ctrl)
+			transactorMock := tmocks.NewTransactorMock(ctrl)
+
+			s := &orderService{
+				repo:       repoMock,
+				outbox:     outboxMock,
+				transactor: transactorMock,
+				log:        slog.Default(),
+				cache:      newTestCache(),
+				val:        validator.New(),
+			}
+
+			tt.prepare(&fields{
+				repoMock:       repoMock,
+				outboxMock:     outboxMock,
+				transactorMock: transactorMock,
+			})
+
+			results, err := s.AddOrders(context.Background(), tt.orders())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			statuses := make([]models.BatchStatus, 0, len(results))
+			for i, res := range results {
+				assert.Equal(t, i, res.Index)
+				statuses = append(statuses, res.Status)
+			}
+			assert.Equal(t, tt.want, statuses)
+
+			assert.Equal(t, len(tt.wantCreated), s.cache.Len())
+			for _, uid := range tt.wantCreated {
+				_, ok := s.cache.Get(uid)
+				assert.True(t, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+var ErrCommit = errors.New("commit error")
+
+// fakeTransactor runs the body like pgdb.Transaction does and then fails
+// the commit if commitErr is set, dropping the after-commit hooks.
+type fakeTransactor struct {
+	commitErr error
+}
+
+func (f *fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	txCtx, hooks := pgdb.WithHooks(ctx)
+
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+
+	if f.commitErr != nil {
+		return f.commitErr
+	}
+
+	hooks.Run()
+
+	return nil
+}
+
+func Test_orderService_CacheAfterCommit(t *testing.T) {
+	t.Parallel()
+
+	address := "new address"
+
+	tests := []struct {
+		name       string
+		commitErr  error
+		prepare    func(repoMock *rmocks.OrderRepositoryMock)
+		call       func(s *orderService) error
+		wantCached bool
+	}{
+		{
+			name:    "AddOrder - Commit Fails",
+			prepare: func(repoMock *rmocks.OrderRepositoryMock) { repoMock.SaveOrderMock.Return(nil) },
+			call: func(s *orderService) error {
+				return s.AddOrder(context.Background(), validOrder("uid1"))
+			},
+			commitErr: ErrCommit,
+		},
+		{
+			name:    "AddOrder - Commit Succeeds",
+			prepare: func(repoMock *rmocks.OrderRepositoryMock) { repoMock.SaveOrderMock.Return(nil) },
+			call: func(s *orderService) error {
+				return s.AddOrder(context.Background(), validOrder("uid1"))
+			},
+			wantCached: true,
+		},
+		{
+			name: "UpdateOrder - Commit Fails",
+			prepare: func(repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrderByUIDMock.Return(validOrder("uid1"), nil)
+				repoMock.UpdateOrderMock.Return(nil)
+			},
+			call: func(s *orderService) error {
+				patch := models.OrderPatch{Delivery: &models.DeliveryPatch{Address: &address}}
+				_, err := s.UpdateOrder(context.Background(), "uid1", patch, 1)
+				return err
+			},
+			commitErr: ErrCommit,
+		},
+		{
+			name: "ChangeOrderStatus - Commit Fails",
+			prepare: func(repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrderByUIDMock.Return(validOrder("uid1"), nil)
+				repoMock.UpdateOrderStatusMock.Return(nil)
+			},
+			call: func(s *orderService) error {
+				_, err := s.ChangeOrderStatus(context.Background(), "uid1", models.StatusPaid)
+				return err
+			},
+			commitErr: ErrCommit,
+		},
+		{
+			name: "ChangeOrderStatus - Commit Succeeds",
+			prepare: func(repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrderByUIDMock.Return(validOrder("uid1"), nil)
+				repoMock.UpdateOrderStatusMock.Return(nil)
+			},
+			call: func(s *orderService) error {
+				_, err := s.ChangeOrderStatus(context.Background(), "uid1", models.StatusPaid)
+				return err
+			},
+			wantCached: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+			outboxMock := rmocks.NewOutboxRepositoryMock(ctrl)
+			outboxMock.AddEventsMock.Return(nil)
+
+			s := &orderService{
+				repo:       repoMock,
+				outbox:     outboxMock,
+				transactor: &fakeTransactor{commitErr: tt.commitErr},
+				log:        slog.Default(),
+				cache:      newTestCache(),
+				val:        validator.New(),
+			}
+
+			tt.prepare(repoMock)
+
+			err := tt.call(s)
+			if tt.commitErr != nil {
+				assert.ErrorIs(t, err, tt.commitErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			_, ok := s.cache.Get("uid1")
+			assert.Equal(t, tt.wantCached, ok)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"github.com/sdvaanyaa/order-service/internal/cache"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"time"
+)
+
+type CacheStats struct {
+	cache.Stats
+	HitRatio           float64             `json:"hit_ratio"`
+	Warmup             models.WarmupStatus `json:"warmup"`
+	LastWarmupDuration string              `json:"last_warmup_duration,omitempty"`
+}
+
+func (s *orderService) CacheStats() CacheStats {
+	stats := CacheStats{
+		Stats:  s.cache.Stats(),
+		Warmup: s.warmup.status(),
+	}
+
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	if d := s.warmup.last(); d > 0 {
+		stats.LastWarmupDuration = d.String()
+	}
+
+	return stats
+}
+
+// ReloadCache drops every cached order and warms the cache again in the
+// background, so it does not depend on the caller's request but ends when
+// the service is closed. It is rejected while any warmup is running. The
+// service stays ready meanwhile, since misses fall through to the database.
+func (s *orderService) ReloadCache() error {
+	if !s.warmup.start(time.Now(), true) {
+		return ErrWarmupRunning
+	}
+
+	s.cache.Purge()
+	s.notFound.Purge()
+	s.log.Info("cache purged for reload")
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		s.runWarmup(s.ctx)
+	}()
+
+	return nil
+}
+
+func (s *orderService) EvictOrder(uid string) {
+	s.cache.Delete(uid)
+	s.notFound.Delete(uid)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/cache"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func Test_orderService_CacheStats(t *testing.T) {
+	t.Parallel()
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+	s := &orderService{
+		repo:  repoMock,
+		log:   slog.Default(),
+		cache: newTestCache(),
+	}
+
+	stats := s.CacheStats()
+	assert.Zero(t, stats.HitRatio)
+	assert.Equal(t, models.WarmupPending, stats.Warmup.State)
+	assert.Empty(t, stats.LastWarmupDuration)
+
+	repoMock.LoadAllOrdersMock.Return([]*models.Order{validOrder("uid1")}, nil)
+	s.WarmCache(context.Background())
+
+	_, _ = s.cache.Get("uid1")
+	_, _ = s.cache.Get("uid1")
+	_, _ = s.cache.Get("uid1")
+	_, _ = s.cache.Get("uid2")
+
+	stats = s.CacheStats()
+	assert.Equal(t, 1, stats.Entries)
+	assert.Positive(t, stats.Bytes)
+	assert.InDelta(t, 0.75, stats.HitRatio, 1e-9)
+	assert.Equal(t, models.WarmupReady, stats.Warmup.State)
+	assert.NotEmpty(t, stats.LastWarmupDuration)
+}
+
+func Test_orderService_ReloadCache(t *testing.T) {
+	t.Parallel()
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+	s := &orderService{
+		repo:     repoMock,
+		log:      slog.Default(),
+		cache:    newTestCache(),
+		notFound: cache.NewNegative(time.Minute, 10),
+		ctx:      context.Background(),
+	}
+	s.cache.Set(validOrder("stale"))
+	s.notFound.Add("uid1")
+
+	loaded := make(chan struct{})
+	repoMock.LoadAllOrdersMock.Set(func(_ context.Context, _ models.LoadOptions) ([]*models.Order, error) {
+		<-loaded
+		return []*models.Order{validOrder("uid1")}, nil
+	})
+
+	assert.NoError(t, s.ReloadCache())
+	assert.ErrorIs(t, s.ReloadCache(), ErrWarmupRunning)
+	assert.Equal(t, models.WarmupRunning, s.WarmupStatus().State)
+	assert.True(t, s.WarmupStatus().Reload)
+	assert.False(t, s.notFound.Has("uid1"))
+
+	close(loaded)
+
+	assert.Eventually(t, func() bool {
+		return s.WarmupStatus().State == models.WarmupReady
+	}, time.Second, 10*time.Millisecond)
+
+	_, ok := s.cache.Peek("stale")
+	assert.False(t, ok)
+	_, ok = s.cache.Peek("uid1")
+	assert.True(t, ok)
+}
+
+func Test_orderService_ReloadCache_EndsOnClose(t *testing.T) {
+	t.Parallel()
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+	ctx, stop := context.WithCancel(context.Background())
+	s := &orderService{
+		repo:     repoMock,
+		log:      slog.Default(),
+		cache:    newTestCache(),
+		notFound: cache.NewNegative(time.Minute, 10),
+		ctx:      ctx,
+		stop:     stop,
+	}
+
+	repoMock.LoadAllOrdersMock.Set(func(ctx context.Context, _ models.LoadOptions) ([]*models.Order, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	assert.NoError(t, s.ReloadCache())
+	assert.NoError(t, s.Close(context.Background()))
+	assert.Equal(t, models.WarmupFailed, s.WarmupStatus().State)
+}
+
+func Test_orderService_EvictOrder(t *testing.T) {
+	t.Parallel()
+
+	s := &orderService{
+		cache:    newTestCache(),
+		notFound: cache.NewNegative(time.Minute, 10),
+	}
+	s.cache.Set(validOrder("uid1"))
+	s.notFound.Add("uid2")
+
+	s.EvictOrder("uid1")
+	s.EvictOrder("uid2")
+
+	assert.Equal(t, 0, s.cache.Len())
+	assert.False(t, s.notFound.Has("uid2"))
+}
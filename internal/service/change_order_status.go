@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+)
+
+func (s *orderService) ChangeOrderStatus(
+	ctx context.Context,
+	uid string,
+	status models.OrderStatus,
+) (*models.Order, error) {
+	if !status.Valid() {
+		return nil, ErrInvalidInput
+	}
+
+	var updated *models.Order
+
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		order, err := s.repo.GetOrderByUID(txCtx, uid, false)
+		if err != nil {
+			return err
+		}
+
+		if !order.Status.CanTransitionTo(status) {
+			return ErrInvalidTransition
+		}
+
+		if err = s.repo.UpdateOrderStatus(txCtx, uid, order.Status, status); err != nil {
+			if errors.Is(err, repository.ErrStatusConflict) {
+				return ErrInvalidTransition
+			}
+			return err
+		}
+
+		copied := *order
+		copied.Status = status
+		copied.Version++
+		if err = s.recordEvents(txCtx, models.EventOrderUpdated, &copied); err != nil {
+			return err
+		}
+
+		updated = &copied
+		pgdb.AfterCommit(txCtx, func() { s.cacheOrder(updated) })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
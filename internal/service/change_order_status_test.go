@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	tmocks "github.com/sdvaanyaa/order-service/pkg/pgdb/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func Test_orderService_ChangeOrderStatus(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		repoMock       *rmocks.OrderRepositoryMock
+		outboxMock     *rmocks.OutboxRepositoryMock
+		transactorMock *tmocks.TransactorMock
+	}
+	type args struct {
+		ctx    context.Context
+		uid    string
+		status models.OrderStatus
+	}
+	tests := []struct {
+		name       string
+		prepare    func(a args, f *fields)
+		args       args
+		wantStatus models.OrderStatus
+		wantErr    error
+	}{
+		{
+			name: "Success",
+			args: args{ctx: context.Background(), uid: "uid1", status: models.StatusPaid},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid, false).
+					Return(&models.Order{OrderUID: a.uid, Status: models.StatusCreated}, nil)
+				f.repoMock.UpdateOrderStatusMock.Expect(a.ctx, a.uid, models.StatusCreated, a.status).Return(nil)
+				f.outboxMock.AddEventsMock.Set(func(_ context.Context, events []models.OutboxEvent) error {
+					assert.Len(t, events, 1)
+					assert.Equal(t, models.EventOrderUpdated, events[0].Type)
+					return nil
+				})
+			},
+			wantStatus: models.StatusPaid,
+		},
+		{
+			name:    "Unknown Status",
+			args:    args{ctx: context.Background(), uid: "uid1", status: "lost"},
+			prepare: func(a args, f *fields) {},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name: "Illegal Transition",
+			args: args{ctx: context.Background(), uid: "uid1", status: models.StatusDelivered},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid, false).
+					Return(&models.Order{OrderUID: a.uid, Status: models.StatusCreated}, nil)
+			},
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name: "Concurrent Change",
+			args: args{ctx: context.Background(), uid: "uid1", status: models.StatusCancelled},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid, false).
+					Return(&models.Order{OrderUID: a.uid, Status: models.StatusPaid}, nil)
+				f.repoMock.UpdateOrderStatusMock.Expect(a.ctx, a.uid, models.StatusPaid, a.status).
+					Return(repository.ErrStatusConflict)
+			},
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name: "Order Not Found",
+			args: args{ctx: context.Background(), uid: "uid1", status: models.StatusPaid},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid, false).Return(nil, repository.ErrOrderNotFound)
+			},
+			wantErr: repository.ErrOrderNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+			outboxMock := rmocks.NewOutboxRepositoryMock(ctrl)
+			transactorMock := tmocks.NewTransactorMock(ctrl)
+
+			s := &orderService{
+				repo:       repoMock,
+				outbox:     outboxMock,
+				transactor: transactorMock,
+				log:        slog.Default(),
+				cache:      newTestCache(),
+			}
+
+			tt.prepare(tt.args, &fields{
+				repoMock:       repoMock,
+				outboxMock:     outboxMock,
+				transactorMock: transactorMock,
+			})
+
+			got, err := s.ChangeOrderStatus(tt.args.ctx, tt.args.uid, tt.args.status)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+
+			cached, _ := s.cache.Get(tt.args.uid)
+			assert.Equal(t, got, cached)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sdvaanyaa/order-service/internal/models"
+)
+
+// validate checks the struct tags first and the business rules after them.
+func (s *orderService) validate(order *models.Order) error {
+	if err := s.val.Struct(order); err != nil {
+		return newValidationError(err)
+	}
+
+	return s.checkConsistency(order)
+}
+
+// checkConsistency runs the business rules the struct tags cannot express.
+// In strict mode a broken rule rejects the order, otherwise it is logged and
+// the order goes through.
+func (s *orderService) checkConsistency(order *models.Order) error {
+	problems := s.consistencyProblems(order)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if !s.validation.Strict() {
+		s.log.Warn("inconsistent order accepted", "uid", order.OrderUID, "problems", problems)
+		return nil
+	}
+
+	return &ValidationError{Fields: problems}
+}
+
+func (s *orderService) consistencyProblems(order *models.Order) []FieldError {
+	var problems []FieldError
+	add := func(field, rule, format string, args ...any) {
+		problems = append(problems, FieldError{Field: field, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	goodsTotal := 0
+	for i, item := range order.Items {
+		if want := item.DiscountedPrice(); item.TotalPrice != want {
+			add(fmt.Sprintf("items[%d].total_price", i), "item_total",
+				"expected %d from price and sale, got %d", want, item.TotalPrice)
+		}
+		if item.TrackNumber != order.TrackNumber {
+			add(fmt.Sprintf("items[%d].track_number", i), "order_track_number",
+				"expected %q, got %q", order.TrackNumber, item.TrackNumber)
+		}
+		goodsTotal += item.TotalPrice
+	}
+
+	payment := order.Payment
+	if payment.GoodsTotal != goodsTotal {
+		add("payment.goods_total", "goods_total",
+			"expected %d as the sum of item totals, got %d", goodsTotal, payment.GoodsTotal)
+	}
+	if want := payment.GoodsTotal + payment.DeliveryCost + payment.CustomFee; payment.Amount != want {
+		add("payment.amount", "payment_amount",
+			"expected %d as goods total, delivery cost and custom fee, got %d", want, payment.Amount)
+	}
+
+	if s.val.Var(payment.Currency, "iso4217") != nil {
+		add("payment.currency", "iso4217", "%q is not an ISO 4217 code", payment.Currency)
+	}
+	if s.val.Var(order.Locale, "bcp47_language_tag") != nil {
+		add("locale", "bcp47_language_tag", "%q is not a known language tag", order.Locale)
+	}
+
+	return problems
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/config"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func Test_orderService_consistencyProblems(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		modify func(order *models.Order)
+		want   []FieldError
+	}{
+		{
+			name:   "Consistent",
+			modify: func(_ *models.Order) {},
+		},
+		{
+			name: "Consistent With Sale",
+			modify: func(order *models.Order) {
+				order.Items[0].Price = 453
+				order.Items[0].Sale = 30
+				order.Items[0].TotalPrice = 317
+				order.Payment.GoodsTotal = 317
+				order.Payment.Amount = 327
+			},
+		},
+		{
+			name: "Item Total",
+			modify: func(order *models.Order) {
+				order.Items[0].Sale = 10
+			},
+			want: []FieldError{{
+				Field:   "items[0].total_price",
+				Rule:    "item_total",
+				Message: "expected 45 from price and sale, got 50",
+			}},
+		},
+		{
+			name: "Item Track Number",
+			modify: func(order *models.Order) {
+				order.Items[0].TrackNumber = "other"
+			},
+			want: []FieldError{{
+				Field:   "items[0].track_number",
+				Rule:    "order_track_number",
+				Message: `expected "track1", got "other"`,
+			}},
+		},
+		{
+			name: "Goods Total",
+			modify: func(order *models.Order) {
+				order.Payment.GoodsTotal = 40
+				order.Payment.Amount = 50
+			},
+			want: []FieldError{{
+				Field:   "payment.goods_total",
+				Rule:    "goods_total",
+				Message: "expected 50 as the sum of item totals, got 40",
+			}},
+		},
+		{
+			name: "Amount",
+			modify: func(order *models.Order) {
+				order.Payment.CustomFee = 5
+			},
+			want: []FieldError{{
+				Field:   "payment.amount",
+				Rule:    "payment_amount",
+				Message: "expected 65 as goods total, delivery cost and custom fee, got 60",
+			}},
+		},
+		{
+			name: "Currency And Locale",
+			modify: func(order *models.Order) {
+				order.Payment.Currency = "XYZ"
+				order.Locale = "not a locale"
+			},
+			want: []FieldError{
+				{Field: "payment.currency", Rule: "iso4217", Message: `"XYZ" is not an ISO 4217 code`},
+				{Field: "locale", Rule: "bcp47_language_tag", Message: `"not a locale" is not a known language tag`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &orderService{val: validator.New()}
+
+			order := validOrder("uid1")
+			tt.modify(order)
+
+			assert.Equal(t, tt.want, s.consistencyProblems(order))
+		})
+	}
+}
+
+func Test_orderService_AddOrder_Consistency(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mode     string
+		wantSave bool
+		wantErr  error
+	}{
+		{
+			name:    "Strict Rejects",
+			mode:    config.ValidationStrict,
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:     "Warn Accepts",
+			mode:     "warn",
+			wantSave: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+			outboxMock := rmocks.NewOutboxRepositoryMock(ctrl)
+
+			s := &orderService{
+				repo:       repoMock,
+				outbox:     outboxMock,
+				transactor: &fakeTransactor{},
+				log:        slog.Default(),
+				val:        validator.New(),
+				cache:      newTestCache(),
+				validation: config.ValidationConfig{Mode: tt.mode},
+			}
+
+			order := validOrder("uid1")
+			order.Payment.Amount = 1000
+
+			if tt.wantSave {
+				repoMock.SaveOrderMock.Return(nil)
+				outboxMock.AddEventsMock.Return(nil)
+			}
+
+			err := s.AddOrder(context.Background(), order)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var verr *ValidationError
+				assert.ErrorAs(t, err, &verr)
+				assert.Len(t, verr.Fields, 1)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
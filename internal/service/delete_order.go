@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+)
+
+func (s *orderService) DeleteOrder(ctx context.Context, uid string) error {
+	if err := s.repo.SoftDeleteOrder(ctx, uid); err != nil {
+		return err
+	}
+
+	s.cache.Delete(uid)
+
+	return nil
+}
+
+func (s *orderService) HardDeleteOrder(ctx context.Context, uid string) error {
+	if err := s.repo.HardDeleteOrder(ctx, uid); err != nil {
+		return err
+	}
+
+	s.cache.Delete(uid)
+
+	return nil
+}
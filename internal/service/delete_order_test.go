@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func Test_orderService_DeleteOrder(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ctx  context.Context
+		uid  string
+		hard bool
+	}
+	tests := []struct {
+		name        string
+		prepare     func(a args, repoMock *rmocks.OrderRepositoryMock)
+		args        args
+		wantErr     error
+		wantEvicted bool
+	}{
+		{
+			name: "Soft Delete",
+			args: args{ctx: context.Background(), uid: "uid1"},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.SoftDeleteOrderMock.Expect(a.ctx, a.uid).Return(nil)
+			},
+			wantEvicted: true,
+		},
+		{
+			name: "Soft Delete - Not Found",
+			args: args{ctx: context.Background(), uid: "uid1"},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.SoftDeleteOrderMock.Expect(a.ctx, a.uid).Return(repository.ErrOrderNotFound)
+			},
+			wantErr: repository.ErrOrderNotFound,
+		},
+		{
+			name: "Hard Delete",
+			args: args{ctx: context.Background(), uid: "uid1", hard: true},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.HardDeleteOrderMock.Expect(a.ctx, a.uid).Return(nil)
+			},
+			wantEvicted: true,
+		},
+		{
+			name: "Hard Delete - Error",
+			args: args{ctx: context.Background(), uid: "uid1", hard: true},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.HardDeleteOrderMock.Expect(a.ctx, a.uid).Return(ErrDB)
+			},
+			wantErr: ErrDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+			s := &orderService{
+				repo:  repoMock,
+				log:   slog.Default(),
+				cache: newTestCache(),
+			}
+			s.cache.Set(&models.Order{OrderUID: "uid1"})
+
+			tt.prepare(tt.args, repoMock)
+
+			var err error
+			if tt.args.hard {
+				err = s.HardDeleteOrder(tt.args.ctx, tt.args.uid)
+			} else {
+				err = s.DeleteOrder(tt.args.ctx, tt.args.uid)
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			_, ok := s.cache.Get(tt.args.uid)
+			assert.Equal(t, tt.wantEvicted, !ok)
+		})
+	}
+}
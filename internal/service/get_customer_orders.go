@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+)
+
+func (s *orderService) GetCustomerOrders(
+	ctx context.Context,
+	customerID string,
+	cursor string,
+	limit int,
+) (*models.CustomerOrders, error) {
+	if customerID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	summary, err := s.repo.GetCustomerSummary(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.ListOrders(ctx, models.OrderFilter{CustomerID: customerID}, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CustomerOrders{
+		Summary:   summary,
+		OrderPage: *page,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func Test_orderService_GetCustomerOrders(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	summary := &models.CustomerSummary{
+		CustomerID:   "cust",
+		OrderCount:   1,
+		TotalSpent:   map[string]int64{"USD": 100},
+		FirstOrderAt: &now,
+		LastOrderAt:  &now,
+	}
+	orders := []*models.Order{{OrderUID: "uid1", CustomerID: "cust", DateCreated: now}}
+	filter := models.OrderFilter{CustomerID: "cust"}
+
+	type args struct {
+		ctx        context.Context
+		customerID string
+	}
+	tests := []struct {
+		name    string
+		prepare func(a args, repoMock *rmocks.OrderRepositoryMock)
+		args    args
+		want    *models.CustomerOrders
+		wantErr error
+	}{
+		{
+			name: "Success",
+			args: args{ctx: context.Background(), customerID: "cust"},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetCustomerSummaryMock.Expect(a.ctx, a.customerID).Return(summary, nil)
+				repoMock.ListOrdersMock.Expect(a.ctx, filter, nil, DefaultListLimit+1).Return(orders, nil)
+			},
+			want: &models.CustomerOrders{
+				Summary:   summary,
+				OrderPage: models.OrderPage{Orders: orders},
+			},
+		},
+		{
+			name:    "Empty Customer ID",
+			args:    args{ctx: context.Background()},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name: "Summary Error",
+			args: args{ctx: context.Background(), customerID: "cust"},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetCustomerSummaryMock.Expect(a.ctx, a.customerID).Return(nil, ErrDB)
+			},
+			wantErr: ErrDB,
+		},
+		{
+			name: "List Error",
+			args: args{ctx: context.Background(), customerID: "cust"},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetCustomerSummaryMock.Expect(a.ctx, a.customerID).Return(summary, nil)
+				repoMock.ListOrdersMock.Expect(a.ctx, filter, nil, DefaultListLimit+1).Return(nil, ErrDB)
+			},
+			wantErr: ErrDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+			s := &orderService{
+				repo: repoMock,
+				log:  slog.Default(),
+			}
+
+			tt.prepare(tt.args, repoMock)
+
+			got, err := s.GetCustomerOrders(tt.args.ctx, tt.args.customerID, "", 0)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/cache"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_orderService_GetOrder_CollapsesConcurrentMisses(t *testing.T) {
+	t.Parallel()
+
+	const callers = 20
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	repoMock.GetOrderByUIDMock.Set(func(_ context.Context, uid string, _ bool) (*models.Order, error) {
+		calls.Add(1)
+		<-release
+		return &models.Order{OrderUID: uid}, nil
+	})
+
+	s := &orderService{
+		repo:  repoMock,
+		log:   slog.Default(),
+		cache: newTestCache(),
+	}
+
+	var started, done sync.WaitGroup
+	for range callers {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			order, err := s.GetOrder(context.Background(), "uid1", false)
+			assert.NoError(t, err)
+			assert.Equal(t, "uid1", order.OrderUID)
+		}()
+	}
+	started.Wait()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func Test_orderService_GetOrder_NegativeCache(t *testing.T) {
+	t.Parallel()
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+	repoMock.GetOrderByUIDMock.Times(1).Return(nil, repository.ErrOrderNotFound)
+
+	s := &orderService{
+		repo:     repoMock,
+		log:      slog.Default(),
+		cache:    newTestCache(),
+		notFound: cache.NewNegative(time.Minute, 100),
+	}
+
+	for range 3 {
+		_, err := s.GetOrder(context.Background(), "missing", false)
+		assert.ErrorIs(t, err, repository.ErrOrderNotFound)
+	}
+
+	s.cacheOrder(&models.Order{OrderUID: "missing"})
+	order, err := s.GetOrder(context.Background(), "missing", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "missing", order.OrderUID)
+}
+
+func Test_orderService_GetOrder_SharedLoadOutlivesCaller(t *testing.T) {
+	t.Parallel()
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	repoMock.GetOrderByUIDMock.Times(1).Set(func(ctx context.Context, uid string, _ bool) (*models.Order, error) {
+		close(started)
+		<-release
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return &models.Order{OrderUID: uid}, nil
+	})
+
+	s := &orderService{
+		repo:  repoMock,
+		log:   slog.Default(),
+		cache: newTestCache(),
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := s.GetOrder(first, "uid1", false)
+		firstErr <- err
+	}()
+	<-started
+
+	second := make(chan *models.Order)
+	go func() {
+		order, err := s.GetOrder(context.Background(), "uid1", false)
+		assert.NoError(t, err)
+		second <- order
+	}()
+
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+
+	close(release)
+	order := <-second
+	assert.Equal(t, "uid1", order.OrderUID)
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+)
+
+func (s *orderService) GetOrdersByTrackNumber(ctx context.Context, trackNumber string) ([]*models.Order, error) {
+	if trackNumber == "" {
+		return nil, ErrInvalidInput
+	}
+
+	orders, err := s.repo.GetOrdersByTrackNumber(ctx, trackNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orders) == 0 {
+		return nil, repository.ErrOrderNotFound
+	}
+
+	return orders, nil
+}
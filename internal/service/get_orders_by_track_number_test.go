@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func Test_orderService_GetOrdersByTrackNumber(t *testing.T) {
+	t.Parallel()
+
+	orders := []*models.Order{{OrderUID: "uid1", TrackNumber: "track1"}}
+
+	type args struct {
+		ctx         context.Context
+		trackNumber string
+	}
+	tests := []struct {
+		name    string
+		prepare func(a args, repoMock *rmocks.OrderRepositoryMock)
+		args    args
+		want    []*models.Order
+		wantErr error
+	}{
+		{
+			name: "Found",
+			args: args{ctx: context.Background(), trackNumber: "track1"},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrdersByTrackNumberMock.Expect(a.ctx, a.trackNumber).Return(orders, nil)
+			},
+			want: orders,
+		},
+		{
+			name: "Not Found",
+			args: args{ctx: context.Background(), trackNumber: "missing"},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrdersByTrackNumberMock.Expect(a.ctx, a.trackNumber).Return(nil, nil)
+			},
+			wantErr: repository.ErrOrderNotFound,
+		},
+		{
+			name:    "Empty Track Number",
+			args:    args{ctx: context.Background()},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name: "Repo Error",
+			args: args{ctx: context.Background(), trackNumber: "track1"},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrdersByTrackNumberMock.Expect(a.ctx, a.trackNumber).Return(nil, ErrDB)
+			},
+			wantErr: ErrDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+			s := &orderService{
+				repo: repoMock,
+				log:  slog.Default(),
+			}
+
+			tt.prepare(tt.args, repoMock)
+
+			got, err := s.GetOrdersByTrackNumber(tt.args.ctx, tt.args.trackNumber)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"github.com/sdvaanyaa/order-service/internal/cache"
+	"github.com/sdvaanyaa/order-service/internal/config"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"time"
+)
+
+func newTestCache() cache.Cache {
+	return cache.NewLRU(config.CacheConfig{MaxEntries: 1000, Shards: 4})
+}
+
+func validOrder(uid string) *models.Order {
+	now := time.Now().UTC()
+	return &models.Order{
+		OrderUID:        uid,
+		TrackNumber:     "track1",
+		Entry:           "entry",
+		Locale:          "en",
+		CustomerID:      "cust",
+		DeliveryService: "serv",
+		Shardkey:        "9",
+		SmID:            99,
+		DateCreated:     now,
+		OofShard:        "1",
+		Status:          models.StatusCreated,
+		Version:         1,
+		Delivery: models.Delivery{
+			Name:    "name",
+			Phone:   "+123",
+			Zip:     "zip",
+			City:    "city",
+			Address: "addr",
+			Region:  "region",
+			Email:   "email@example.com",
+		},
+		Payment: models.Payment{
+			Transaction:  "tx-" + uid,
+			Currency:     "USD",
+			Provider:     "prov",
+			Amount:       60,
+			PaymentDt:    now.Unix(),
+			Bank:         "bank",
+			DeliveryCost: 10,
+			GoodsTotal:   50,
+		},
+		Items: []models.Item{
+			{
+				ChrtID:      1,
+				TrackNumber: "track1",
+				Price:       50,
+				Name:        "item",
+				Size:        "M",
+				TotalPrice:  50,
+				NmID:        2,
+				Brand:       "brand",
+				Status:      200,
+			},
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+)
+
+// changeQueueSize caps the notifications waiting for the refresh worker.
+const changeQueueSize = 1024
+
+// WatchChanges keeps the cache in line with writes made by other replicas
+// until ctx is done. Notifications are refreshed by a separate worker, so a
+// slow reload does not hold up the listener connection.
+func (s *orderService) WatchChanges(ctx context.Context) {
+	changes := make(chan repository.OrderChange, changeQueueSize)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for change := range changes {
+			s.refreshOrder(ctx, change)
+		}
+	}()
+
+	s.listener.Listen(
+		ctx,
+		repository.OrderChangedChannel,
+		func(payload string) { s.queueChange(changes, payload) },
+		s.resetCache,
+	)
+
+	close(changes)
+	<-done
+}
+
+// queueChange hands a notification to the refresh worker. When the worker
+// falls too far behind the change is dropped along with the whole cache,
+// as after a reconnect.
+func (s *orderService) queueChange(changes chan<- repository.OrderChange, payload string) {
+	select {
+	case changes <- parseChange(payload):
+	default:
+		s.log.Warn("order change queue full, dropping cache")
+		s.cache.Purge()
+		s.notFound.Purge()
+	}
+}
+
+// parseChange decodes a notification. A bare uid, as sent by replicas that
+// predate versioned payloads, is taken as a change of unknown version.
+func parseChange(payload string) repository.OrderChange {
+	var change repository.OrderChange
+	if err := json.Unmarshal([]byte(payload), &change); err != nil || change.UID == "" {
+		return repository.OrderChange{UID: payload}
+	}
+
+	return change
+}
+
+// refreshOrder reloads a cached order after it was written somewhere. Our own
+// writes, and any write the cached copy already includes, carry a version no
+// newer than the cached one, so they are skipped without a database call.
+func (s *orderService) refreshOrder(ctx context.Context, change repository.OrderChange) {
+	uid := change.UID
+	s.notFound.Delete(uid)
+
+	cached, ok := s.cache.Peek(uid)
+	if !ok {
+		// keeps a running warmup from caching the copy it read before
+		s.cache.Delete(uid)
+		return
+	}
+
+	if change.Version > 0 && cached.Version >= change.Version {
+		return
+	}
+
+	order, err := s.repo.GetOrderByUID(ctx, uid, false)
+	switch {
+	case errors.Is(err, repository.ErrOrderNotFound):
+		s.cache.Delete(uid)
+	case err != nil:
+		s.log.Warn("failed to refresh cached order", "uid", uid, "err", err)
+		s.cache.Delete(uid)
+	case order.Version > cached.Version:
+		s.cache.Set(order)
+	}
+}
+
+// resetCache drops everything cached, since changes may have been missed
+// while the listener was disconnected.
+func (s *orderService) resetCache() {
+	s.log.Warn("order change listener reconnected, dropping cache")
+	s.cache.Purge()
+	s.notFound.Purge()
+}
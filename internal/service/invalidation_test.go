@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/cache"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeListener replays payloads and reconnects as if they came from Postgres.
+type fakeListener struct {
+	payloads  []string
+	reconnect bool
+}
+
+func (f *fakeListener) Listen(_ context.Context, _ string, onNotify func(string), onReconnect func()) {
+	for _, payload := range f.payloads {
+		onNotify(payload)
+	}
+	if f.reconnect {
+		onReconnect()
+	}
+}
+
+func Test_orderService_refreshOrder(t *testing.T) {
+	t.Parallel()
+
+	cachedOrder := validOrder("uid1")
+	newerOrder := validOrder("uid1")
+	newerOrder.Version = 2
+	newerOrder.Status = models.StatusPaid
+
+	type args struct {
+		ctx    context.Context
+		change repository.OrderChange
+	}
+	tests := []struct {
+		name      string
+		cached    *models.Order
+		prepare   func(a args, repoMock *rmocks.OrderRepositoryMock)
+		args      args
+		wantOrder *models.Order
+	}{
+		{
+			name: "Not Cached",
+			args: args{ctx: context.Background(), change: repository.OrderChange{UID: "uid1", Version: 2}},
+			prepare: func(_ args, _ *rmocks.OrderRepositoryMock) {
+			},
+		},
+		{
+			name:   "Changed Elsewhere",
+			cached: cachedOrder,
+			args:   args{ctx: context.Background(), change: repository.OrderChange{UID: "uid1", Version: 2}},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrderByUIDMock.Expect(a.ctx, a.change.UID, false).Return(newerOrder, nil)
+			},
+			wantOrder: newerOrder,
+		},
+		{
+			name:      "Own Write",
+			cached:    newerOrder,
+			args:      args{ctx: context.Background(), change: repository.OrderChange{UID: "uid1", Version: 2}},
+			prepare:   func(_ args, _ *rmocks.OrderRepositoryMock) {},
+			wantOrder: newerOrder,
+		},
+		{
+			name:   "Unknown Version",
+			cached: newerOrder,
+			args:   args{ctx: context.Background(), change: repository.OrderChange{UID: "uid1"}},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrderByUIDMock.Expect(a.ctx, a.change.UID, false).Return(validOrder("uid1"), nil)
+			},
+			wantOrder: newerOrder,
+		},
+		{
+			name:   "Deleted Elsewhere",
+			cached: cachedOrder,
+			args:   args{ctx: context.Background(), change: repository.OrderChange{UID: "uid1", Version: 2}},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrderByUIDMock.Expect(a.ctx, a.change.UID, false).Return(nil, repository.ErrOrderNotFound)
+			},
+		},
+		{
+			name:   "Repo Error",
+			cached: cachedOrder,
+			args:   args{ctx: context.Background(), change: repository.OrderChange{UID: "uid1", Version: 2}},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.GetOrderByUIDMock.Expect(a.ctx, a.change.UID, false).Return(nil, ErrDB)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+			s := &orderService{
+				repo:     repoMock,
+				log:      slog.Default(),
+				cache:    newTestCache(),
+				notFound: cache.NewNegative(time.Minute, 10),
+			}
+			if tt.cached != nil {
+				s.cache.Set(tt.cached)
+			}
+			s.notFound.Add(tt.args.change.UID)
+
+			tt.prepare(tt.args, repoMock)
+
+			s.refreshOrder(tt.args.ctx, tt.args.change)
+
+			got, ok := s.cache.Peek(tt.args.change.UID)
+			if tt.wantOrder == nil {
+				assert.False(t, ok)
+			} else {
+				assert.True(t, ok)
+				assert.Same(t, tt.wantOrder, got)
+			}
+			assert.False(t, s.notFound.Has(tt.args.change.UID))
+		})
+	}
+}
+
+func Test_orderService_WatchChanges(t *testing.T) {
+	t.Parallel()
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+	s := &orderService{
+		repo:     repoMock,
+		listener: &fakeListener{payloads: []string{`{"uid":"uid1","version":2}`}, reconnect: true},
+		log:      slog.Default(),
+		cache:    newTestCache(),
+		notFound: cache.NewNegative(time.Minute, 10),
+	}
+	s.cache.Set(validOrder("uid1"))
+	s.cache.Set(validOrder("uid2"))
+	s.notFound.Add("uid3")
+
+	// the reconnect may purge uid1 before the worker gets to it
+	repoMock.GetOrderByUIDMock.Optional().Return(nil, repository.ErrOrderNotFound)
+
+	s.WatchChanges(context.Background())
+
+	assert.Equal(t, 0, s.cache.Len())
+	assert.False(t, s.notFound.Has("uid3"))
+}
+
+func Test_parseChange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload string
+		want    repository.OrderChange
+	}{
+		{
+			name:    "Versioned",
+			payload: `{"uid":"uid1","version":3}`,
+			want:    repository.OrderChange{UID: "uid1", Version: 3},
+		},
+		{
+			name:    "Deleted",
+			payload: `{"uid":"uid1","version":null}`,
+			want:    repository.OrderChange{UID: "uid1"},
+		},
+		{
+			name:    "Bare UID",
+			payload: "uid1",
+			want:    repository.OrderChange{UID: "uid1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, parseChange(tt.payload))
+		})
+	}
+}
+
+func Test_orderService_queueChange_Full(t *testing.T) {
+	t.Parallel()
+
+	s := &orderService{
+		log:      slog.Default(),
+		cache:    newTestCache(),
+		notFound: cache.NewNegative(time.Minute, 10),
+	}
+	s.cache.Set(validOrder("uid1"))
+
+	changes := make(chan repository.OrderChange, 1)
+	s.queueChange(changes, `{"uid":"uid1","version":2}`)
+	assert.Equal(t, 1, s.cache.Len())
+
+	s.queueChange(changes, `{"uid":"uid2","version":2}`)
+	assert.Equal(t, 0, s.cache.Len())
+	assert.Equal(t, repository.OrderChange{UID: "uid1", Version: 2}, <-changes)
+}
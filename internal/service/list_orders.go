@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sdvaanyaa/order-service/internal/models"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+func (s *orderService) ListOrders(
+	ctx context.Context,
+	filter models.OrderFilter,
+	cursor string,
+	limit int,
+) (*models.OrderPage, error) {
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	var after *models.OrderCursor
+	if cursor != "" {
+		decoded, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+		}
+		after = decoded
+	}
+
+	// one extra row tells whether there is a next page
+	orders, err := s.repo.ListOrders(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.OrderPage{Orders: orders}
+	if len(orders) > limit {
+		page.Orders = orders[:limit]
+		last := page.Orders[limit-1]
+		page.NextCursor = encodeCursor(&models.OrderCursor{
+			DateCreated: last.DateCreated,
+			OrderUID:    last.OrderUID,
+		})
+	}
+
+	if page.Orders == nil {
+		page.Orders = []*models.Order{}
+	}
+
+	return page, nil
+}
+
+func encodeCursor(c *models.OrderCursor) string {
+	raw, _ := json.Marshal(c) //nolint:errchkjson // plain struct, cannot fail
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(cursor string) (*models.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var c models.OrderCursor
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+
+	if c.OrderUID == "" || c.DateCreated.IsZero() {
+		return nil, errors.New("incomplete cursor")
+	}
+
+	return &c, nil
+}
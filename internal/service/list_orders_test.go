@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func Test_orderService_ListOrders(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	orders := []*models.Order{
+		{OrderUID: "uid3", DateCreated: now},
+		{OrderUID: "uid2", DateCreated: now.Add(-time.Minute)},
+		{OrderUID: "uid1", DateCreated: now.Add(-2 * time.Minute)},
+	}
+	filter := models.OrderFilter{CustomerID: "cust"}
+	cursor := encodeCursor(&models.OrderCursor{DateCreated: now, OrderUID: "uid3"})
+
+	type args struct {
+		ctx    context.Context
+		cursor string
+		limit  int
+	}
+	tests := []struct {
+		name           string
+		prepare        func(a args, repoMock *rmocks.OrderRepositoryMock)
+		args           args
+		wantUIDs       []string
+		wantNextCursor bool
+		wantErr        error
+	}{
+		{
+			name: "First Page With Next",
+			args: args{ctx: context.Background(), limit: 2},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.ListOrdersMock.Expect(a.ctx, filter, nil, 3).Return(orders, nil)
+			},
+			wantUIDs:       []string{"uid3", "uid2"},
+			wantNextCursor: true,
+		},
+		{
+			name: "Last Page",
+			args: args{ctx: context.Background(), cursor: cursor, limit: 2},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				after := &models.OrderCursor{DateCreated: now, OrderUID: "uid3"}
+				repoMock.ListOrdersMock.Expect(a.ctx, filter, after, 3).Return(orders[1:], nil)
+			},
+			wantUIDs: []string{"uid2", "uid1"},
+		},
+		{
+			name: "Default Limit",
+			args: args{ctx: context.Background()},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.ListOrdersMock.Expect(a.ctx, filter, nil, DefaultListLimit+1).Return(nil, nil)
+			},
+			wantUIDs: []string{},
+		},
+		{
+			name:    "Malformed Cursor",
+			args:    args{ctx: context.Background(), cursor: "%%%", limit: 2},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name: "Repo Error",
+			args: args{ctx: context.Background(), limit: 2},
+			prepare: func(a args, repoMock *rmocks.OrderRepositoryMock) {
+				repoMock.ListOrdersMock.Expect(a.ctx, filter, nil, 3).Return(nil, ErrDB)
+			},
+			wantErr: ErrDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+			s := &orderService{
+				repo: repoMock,
+				log:  slog.Default(),
+			}
+
+			tt.prepare(tt.args, repoMock)
+
+			page, err := s.ListOrders(tt.args.ctx, filter, tt.args.cursor, tt.args.limit)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			uids := make([]string, 0, len(page.Orders))
+			for _, o := range page.Orders {
+				uids = append(uids, o.OrderUID)
+			}
+			assert.Equal(t, tt.wantUIDs, uids)
+			assert.Equal(t, tt.wantNextCursor, page.NextCursor != "")
+		})
+	}
+}
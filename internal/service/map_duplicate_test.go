@@ -0,0 +1,51 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Test_mapDuplicate covers how unique violations reported by the repository
+// reach callers. Concurrent inserts of one order are resolved by the unique
+// constraints in Postgres, so the losers see the same errors as here.
+func Test_mapDuplicate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		err     error
+		want    error
+		notWant error
+	}{
+		{
+			name: "Order Exists",
+			err:  fmt.Errorf("transaction failed: %w", repository.ErrOrderExists),
+			want: ErrOrderAlreadyExists,
+		},
+		{
+			name:    "Payment Transaction Of Another Order",
+			err:     fmt.Errorf("transaction failed: %w", repository.ErrDuplicateTransaction),
+			want:    ErrTransactionUsed,
+			notWant: ErrOrderAlreadyExists,
+		},
+		{
+			name:    "Other Error",
+			err:     ErrDB,
+			want:    ErrDB,
+			notWant: ErrOrderAlreadyExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := mapDuplicate(tt.err)
+			assert.ErrorIs(t, err, tt.want)
+			if tt.notWant != nil {
+				assert.NotErrorIs(t, err, tt.notWant)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"time"
+)
+
+// recordEvents adds an eventType event for every order to the outbox. It
+// runs in the transaction that wrote the orders, so an event is published
+// exactly when the change is committed.
+func (s *orderService) recordEvents(txCtx context.Context, eventType string, orders ...*models.Order) error {
+	now := time.Now().UTC()
+	events := make([]models.OutboxEvent, 0, len(orders))
+
+	for _, order := range orders {
+		event := models.OrderEvent{
+			ID:         uuid.NewString(),
+			Type:       eventType,
+			OrderUID:   order.OrderUID,
+			Version:    order.Version,
+			OccurredAt: now,
+			Order:      order,
+		}
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshal %s event: %w", eventType, err)
+		}
+
+		events = append(events, models.OutboxEvent{
+			EventID:  event.ID,
+			OrderUID: order.OrderUID,
+			Type:     eventType,
+			Payload:  payload,
+		})
+	}
+
+	return s.outbox.AddEvents(txCtx, events)
+}
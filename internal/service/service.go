@@ -4,111 +4,214 @@ import (
 	"context"
 	"errors"
 	"github.com/go-playground/validator/v10"
+	"github.com/sdvaanyaa/order-service/internal/cache"
+	"github.com/sdvaanyaa/order-service/internal/config"
 	"github.com/sdvaanyaa/order-service/internal/models"
 	"github.com/sdvaanyaa/order-service/internal/repository"
+	"github.com/sdvaanyaa/order-service/pkg/lifecycle"
 	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+	"golang.org/x/sync/singleflight"
 	"log/slog"
 	"sync"
+	"time"
 )
 
+const DefaultLoadTimeout = 5 * time.Second
+
 var (
 	ErrInvalidInput       = errors.New("invalid input")
 	ErrOrderAlreadyExists = errors.New("order already exists")
+	// ErrTransactionUsed means the payment transaction belongs to another
+	// order. Unlike ErrOrderAlreadyExists it is not a replay of a stored order.
+	ErrTransactionUsed   = errors.New("payment transaction already used")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrVersionConflict   = repository.ErrVersionConflict
+	ErrWarmupRunning     = errors.New("cache warmup already running")
 )
 
 type OrderService interface {
 	AddOrder(ctx context.Context, order *models.Order) error
-	GetOrder(ctx context.Context, uid string) (*models.Order, error)
+	AddOrders(ctx context.Context, orders []*models.Order) ([]models.BatchResult, error)
+	GetOrder(ctx context.Context, uid string, includeDeleted bool) (*models.Order, error)
+	ListOrders(ctx context.Context, filter models.OrderFilter, cursor string, limit int) (*models.OrderPage, error)
+	GetOrdersByTrackNumber(ctx context.Context, trackNumber string) ([]*models.Order, error)
+	GetCustomerOrders(ctx context.Context, customerID string, cursor string, limit int) (*models.CustomerOrders, error)
+	ChangeOrderStatus(ctx context.Context, uid string, status models.OrderStatus) (*models.Order, error)
+	UpdateOrder(ctx context.Context, uid string, patch models.OrderPatch, version int) (*models.Order, error)
+	DeleteOrder(ctx context.Context, uid string) error
+	HardDeleteOrder(ctx context.Context, uid string) error
+	WarmCache(ctx context.Context)
+	WarmupStatus() models.WarmupStatus
+	WatchChanges(ctx context.Context)
+	CacheStats() CacheStats
+	ReloadCache() error
+	EvictOrder(uid string)
+	// Close stops the work the service runs in the background on its own
+	// and waits for it until ctx is done.
+	Close(ctx context.Context) error
 }
 
 type orderService struct {
 	repo       repository.OrderRepository
+	outbox     repository.OutboxRepository
 	transactor pgdb.Transactor
+	listener   pgdb.Listener
 	log        *slog.Logger
-	cache      map[string]*models.Order
-	mu         sync.RWMutex
+	cache      cache.Cache
+	notFound   *cache.Negative
+	loads      singleflight.Group
 	val        *validator.Validate
+	cfg        config.CacheConfig
+	validation config.ValidationConfig
+	warmup     warmupTracker
+
+	// ctx ends on Close; background work not tied to a caller runs under it
+	ctx  context.Context
+	stop context.CancelFunc
+	jobs sync.WaitGroup
 }
 
 func New(
 	repo repository.OrderRepository,
+	outbox repository.OutboxRepository,
 	transactor pgdb.Transactor,
+	listener pgdb.Listener,
 	log *slog.Logger,
 	val *validator.Validate,
+	orderCache cache.Cache,
+	notFound *cache.Negative,
+	cfg config.CacheConfig,
+	validation config.ValidationConfig,
 ) OrderService {
-	svc := &orderService{
+	ctx, stop := context.WithCancel(context.Background())
+
+	return &orderService{
+		ctx:        ctx,
+		stop:       stop,
 		repo:       repo,
+		outbox:     outbox,
 		transactor: transactor,
+		listener:   listener,
 		log:        log,
-		cache:      make(map[string]*models.Order),
+		cache:      orderCache,
+		notFound:   notFound,
 		val:        val,
+		cfg:        cfg,
+		validation: validation,
 	}
+}
 
-	svc.loadCache(context.Background())
-
-	return svc
+func (s *orderService) Close(ctx context.Context) error {
+	s.stop()
+	return lifecycle.Wait(ctx, &s.jobs)
 }
 
 func (s *orderService) AddOrder(ctx context.Context, order *models.Order) error {
-	if err := s.val.Struct(order); err != nil {
-		return ErrInvalidInput
-	}
-
-	existingOrders, err := s.repo.GetOrderByUID(ctx, order.OrderUID)
-	if err != nil && !errors.Is(err, repository.ErrOrderNotFound) {
+	if err := s.validate(order); err != nil {
 		return err
 	}
 
-	if existingOrders != nil {
-		return ErrOrderAlreadyExists
-	}
+	order.Status = models.StatusCreated
+	order.Version = 1
 
-	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
-		err = s.repo.SaveOrder(txCtx, order)
+	// duplicates are caught by the unique constraints on insert, so two
+	// concurrent submissions of the same order cannot both get through
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		err := s.repo.SaveOrder(txCtx, order)
 		if err != nil {
 			return err
 		}
 
-		s.mu.Lock()
-		s.cache[order.OrderUID] = order
-		s.mu.Unlock()
+		if err = s.recordEvents(txCtx, models.EventOrderCreated, order); err != nil {
+			return err
+		}
+
+		pgdb.AfterCommit(txCtx, func() { s.cacheOrder(order) })
 
 		return nil
 	})
+
+	return mapDuplicate(err)
+}
+
+// mapDuplicate turns unique violations reported by the repository into
+// service errors.
+func mapDuplicate(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrOrderExists):
+		return ErrOrderAlreadyExists
+	case errors.Is(err, repository.ErrDuplicateTransaction):
+		return ErrTransactionUsed
+	default:
+		return err
+	}
 }
 
-func (s *orderService) GetOrder(ctx context.Context, uid string) (*models.Order, error) {
-	s.mu.RLock()
-	order, ok := s.cache[uid]
-	s.mu.RUnlock()
+func (s *orderService) GetOrder(ctx context.Context, uid string, includeDeleted bool) (*models.Order, error) {
+	order, ok := s.cache.Get(uid)
 	if ok {
 		return order, nil
 	}
 
-	order, err := s.repo.GetOrderByUID(ctx, uid)
-	if err != nil {
-		return nil, err
+	if !includeDeleted && s.notFound.Has(uid) {
+		return nil, repository.ErrOrderNotFound
 	}
 
-	if order != nil {
-		s.mu.Lock()
-		s.cache[uid] = order
-		s.mu.Unlock()
+	return s.loadOrder(ctx, uid, includeDeleted)
+}
+
+// loadOrder fetches an uncached order. Concurrent misses for the same uid
+// share a single repository call. The call is detached from the caller
+// that started it, so its cancellation does not fail the others, and is
+// bounded by the load timeout instead.
+func (s *orderService) loadOrder(ctx context.Context, uid string, includeDeleted bool) (*models.Order, error) {
+	key := uid
+	if includeDeleted {
+		key = "deleted\x00" + uid
 	}
 
-	return order, nil
-}
+	loadCtx := context.WithoutCancel(ctx)
+	ch := s.loads.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(loadCtx, s.loadTimeout())
+		defer cancel()
+
+		order, err := s.repo.GetOrderByUID(ctx, uid, includeDeleted)
+		if err != nil {
+			if errors.Is(err, repository.ErrOrderNotFound) && !includeDeleted {
+				s.notFound.Add(uid)
+			}
+			return nil, err
+		}
+
+		if order != nil && order.DeletedAt == nil {
+			s.cache.Set(order)
+		}
 
-func (s *orderService) loadCache(ctx context.Context) {
-	cached, err := s.repo.LoadAllOrders(ctx)
-	if err != nil {
-		s.log.Error("failed to load cache", "err", err)
-		return
+		return order, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		order, _ := res.Val.(*models.Order)
+		return order, nil
 	}
+}
 
-	s.mu.Lock()
-	s.cache = cached
-	s.mu.Unlock()
+func (s *orderService) loadTimeout() time.Duration {
+	if s.cfg.LoadTimeout <= 0 {
+		return DefaultLoadTimeout
+	}
+	return s.cfg.LoadTimeout
+}
 
-	s.log.Info("cache loaded", "count", len(cached))
+// cacheOrder stores an order that was just written and forgets that it
+// was ever missing.
+func (s *orderService) cacheOrder(order *models.Order) {
+	s.cache.Set(order)
+	s.notFound.Delete(order.OrderUID)
 }
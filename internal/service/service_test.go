@@ -5,6 +5,8 @@ import (
 	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/cache"
+	"github.com/sdvaanyaa/order-service/internal/config"
 	"github.com/sdvaanyaa/order-service/internal/models"
 	"github.com/sdvaanyaa/order-service/internal/repository"
 	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
@@ -74,6 +76,7 @@ func Test_orderService_AddOrder(t *testing.T) {
 
 	type fields struct {
 		repoMock       *rmocks.OrderRepositoryMock
+		outboxMock     *rmocks.OutboxRepositoryMock
 		transactorMock *tmocks.TransactorMock
 	}
 	type args struct {
@@ -94,7 +97,6 @@ func Test_orderService_AddOrder(t *testing.T) {
 				order: order,
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, order.OrderUID).Return(nil, repository.ErrOrderNotFound)
 				f.transactorMock.WithinTransactionMock.Set(func(_ context.Context, fn func(context.Context) error) error {
 					return fn(a.ctx)
 				})
@@ -102,10 +104,31 @@ func Test_orderService_AddOrder(t *testing.T) {
 					assert.Equal(t, order, o)
 					return nil
 				})
+				f.outboxMock.AddEventsMock.Set(func(_ context.Context, events []models.OutboxEvent) error {
+					assert.Len(t, events, 1)
+					assert.Equal(t, models.EventOrderCreated, events[0].Type)
+					assert.Equal(t, order.OrderUID, events[0].OrderUID)
+					return nil
+				})
 			},
 			wantErr:    nil,
 			wantCached: true,
 		},
+		{
+			name: "Outbox Error",
+			args: args{
+				ctx:   context.Background(),
+				order: order,
+			},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(_ context.Context, fn func(context.Context) error) error {
+					return fn(a.ctx)
+				})
+				f.repoMock.SaveOrderMock.Return(nil)
+				f.outboxMock.AddEventsMock.Return(ErrDB)
+			},
+			wantErr: ErrDB,
+		},
 		{
 			name: "Invalid Input",
 			args: args{
@@ -122,18 +145,38 @@ func Test_orderService_AddOrder(t *testing.T) {
 				order: order,
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, order.OrderUID).Return(&models.Order{}, nil)
+				f.transactorMock.WithinTransactionMock.Set(func(_ context.Context, fn func(context.Context) error) error {
+					return fn(a.ctx)
+				})
+				f.repoMock.SaveOrderMock.Return(repository.ErrOrderExists)
 			},
 			wantErr: ErrOrderAlreadyExists,
 		},
 		{
-			name: "Repo Check Error",
+			name: "Payment Transaction Already Exists",
+			args: args{
+				ctx:   context.Background(),
+				order: order,
+			},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(_ context.Context, fn func(context.Context) error) error {
+					return fn(a.ctx)
+				})
+				f.repoMock.SaveOrderMock.Return(repository.ErrDuplicateTransaction)
+			},
+			wantErr: ErrTransactionUsed,
+		},
+		{
+			name: "Repo Save Error",
 			args: args{
 				ctx:   context.Background(),
 				order: order,
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, order.OrderUID).Return(nil, ErrDB)
+				f.transactorMock.WithinTransactionMock.Set(func(_ context.Context, fn func(context.Context) error) error {
+					return fn(a.ctx)
+				})
+				f.repoMock.SaveOrderMock.Return(ErrDB)
 			},
 			wantErr: ErrDB,
 		},
@@ -144,7 +187,6 @@ func Test_orderService_AddOrder(t *testing.T) {
 				order: order,
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, order.OrderUID).Return(nil, repository.ErrOrderNotFound)
 				f.transactorMock.WithinTransactionMock.Return(ErrTx)
 			},
 			wantErr: ErrTx,
@@ -156,18 +198,21 @@ func Test_orderService_AddOrder(t *testing.T) {
 
 			ctrl := minimock.NewController(t)
 			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+			outboxMock := rmocks.NewOutboxRepositoryMock(ctrl)
 			transactorMock := tmocks.NewTransactorMock(ctrl)
 
 			s := &orderService{
 				repo:       repoMock,
+				outbox:     outboxMock,
 				transactor: transactorMock,
 				log:        slog.Default(),
-				cache:      make(map[string]*models.Order),
+				cache:      newTestCache(),
 				val:        validator.New(),
 			}
 
 			tt.prepare(tt.args, &fields{
 				repoMock:       repoMock,
+				outboxMock:     outboxMock,
 				transactorMock: transactorMock,
 			})
 
@@ -179,9 +224,7 @@ func Test_orderService_AddOrder(t *testing.T) {
 			}
 
 			if tt.wantCached {
-				s.mu.RLock()
-				_, ok := s.cache[tt.args.order.OrderUID]
-				s.mu.RUnlock()
+				_, ok := s.cache.Get(tt.args.order.OrderUID)
 				assert.True(t, ok)
 			}
 		})
@@ -195,7 +238,7 @@ func Test_orderService_GetOrder(t *testing.T) {
 	type fields struct {
 		repoMock       *rmocks.OrderRepositoryMock
 		transactorMock *tmocks.TransactorMock
-		cache          map[string]*models.Order
+		cache          cache.Cache
 	}
 	type args struct {
 		ctx context.Context
@@ -215,7 +258,7 @@ func Test_orderService_GetOrder(t *testing.T) {
 				uid: "uid1",
 			},
 			prepare: func(a args, f *fields) {
-				f.cache["uid1"] = order
+				f.cache.Set(order)
 			},
 			want: order,
 		},
@@ -226,7 +269,7 @@ func Test_orderService_GetOrder(t *testing.T) {
 				uid: "uid1",
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid).Return(order, nil)
+				f.repoMock.GetOrderByUIDMock.Expect(minimock.AnyContext, a.uid, false).Return(order, nil)
 			},
 			want: order,
 		},
@@ -237,7 +280,7 @@ func Test_orderService_GetOrder(t *testing.T) {
 				uid: "uid1",
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid).Return(nil, repository.ErrOrderNotFound)
+				f.repoMock.GetOrderByUIDMock.Expect(minimock.AnyContext, a.uid, false).Return(nil, repository.ErrOrderNotFound)
 			},
 			wantErr: repository.ErrOrderNotFound,
 		},
@@ -248,7 +291,7 @@ func Test_orderService_GetOrder(t *testing.T) {
 				uid: "uid1",
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid).Return(nil, ErrDB)
+				f.repoMock.GetOrderByUIDMock.Expect(minimock.AnyContext, a.uid, false).Return(nil, ErrDB)
 			},
 			wantErr: ErrDB,
 		},
@@ -260,23 +303,23 @@ func Test_orderService_GetOrder(t *testing.T) {
 			ctrl := minimock.NewController(t)
 			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
 			transactorMock := tmocks.NewTransactorMock(ctrl)
-			cache := make(map[string]*models.Order)
+			orderCache := newTestCache()
 
 			s := &orderService{
 				repo:       repoMock,
 				transactor: transactorMock,
 				log:        slog.Default(),
-				cache:      cache,
+				cache:      orderCache,
 				val:        validator.New(),
 			}
 
 			tt.prepare(tt.args, &fields{
 				repoMock:       repoMock,
 				transactorMock: transactorMock,
-				cache:          cache,
+				cache:          orderCache,
 			})
 
-			got, err := s.GetOrder(tt.args.ctx, tt.args.uid)
+			got, err := s.GetOrder(tt.args.ctx, tt.args.uid, false)
 			if tt.wantErr != nil {
 				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
@@ -287,12 +330,12 @@ func Test_orderService_GetOrder(t *testing.T) {
 	}
 }
 
-func Test_orderService_loadCache(t *testing.T) {
+func Test_orderService_WarmCache(t *testing.T) {
 	t.Parallel()
 
 	now := time.Now().UTC()
-	orders := map[string]*models.Order{
-		"uid1": {
+	orders := []*models.Order{
+		{
 			OrderUID:          "uid1",
 			TrackNumber:       "track1",
 			Entry:             "entry",
@@ -339,7 +382,7 @@ func Test_orderService_loadCache(t *testing.T) {
 				},
 			},
 		},
-		"uid2": {
+		{
 			OrderUID:          "uid2",
 			TrackNumber:       "track2",
 			Entry:             "entry",
@@ -396,10 +439,11 @@ func Test_orderService_loadCache(t *testing.T) {
 	}
 	tests := []struct {
 		name         string
+		cfg          config.CacheConfig
 		prepare      func(a args, f *fields)
 		args         args
 		wantCacheLen int
-		wantErr      bool
+		wantStatus   models.WarmupStatus
 	}{
 		{
 			name: "Success - Load Orders",
@@ -407,10 +451,32 @@ func Test_orderService_loadCache(t *testing.T) {
 				ctx: context.Background(),
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.LoadAllOrdersMock.Expect(a.ctx).Return(orders, nil)
+				f.repoMock.LoadAllOrdersMock.
+					Expect(a.ctx, models.LoadOptions{Limit: DefaultWarmupPageSize}).
+					Return(orders, nil)
+			},
+			wantCacheLen: 2,
+			wantStatus:   models.WarmupStatus{State: models.WarmupReady, Loaded: 2},
+		},
+		{
+			name: "Success - Paged Up To Limit",
+			cfg:  config.CacheConfig{WarmupPageSize: 1, WarmupLimit: 2},
+			args: args{
+				ctx: context.Background(),
+			},
+			prepare: func(a args, f *fields) {
+				f.repoMock.LoadAllOrdersMock.
+					When(a.ctx, models.LoadOptions{Limit: 1}).
+					Then(orders[:1], nil)
+				f.repoMock.LoadAllOrdersMock.
+					When(a.ctx, models.LoadOptions{
+						After: &models.OrderCursor{DateCreated: now, OrderUID: "uid1"},
+						Limit: 1,
+					}).
+					Then(orders[1:], nil)
 			},
 			wantCacheLen: 2,
-			wantErr:      false,
+			wantStatus:   models.WarmupStatus{State: models.WarmupReady, Loaded: 2},
 		},
 		{
 			name: "Error - DB Fail",
@@ -418,10 +484,12 @@ func Test_orderService_loadCache(t *testing.T) {
 				ctx: context.Background(),
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.LoadAllOrdersMock.Expect(a.ctx).Return(nil, ErrDB)
+				f.repoMock.LoadAllOrdersMock.
+					Expect(a.ctx, models.LoadOptions{Limit: DefaultWarmupPageSize}).
+					Return(nil, ErrDB)
 			},
 			wantCacheLen: 0,
-			wantErr:      true,
+			wantStatus:   models.WarmupStatus{State: models.WarmupFailed, Error: ErrDB.Error()},
 		},
 		{
 			name: "Empty DB",
@@ -429,10 +497,12 @@ func Test_orderService_loadCache(t *testing.T) {
 				ctx: context.Background(),
 			},
 			prepare: func(a args, f *fields) {
-				f.repoMock.LoadAllOrdersMock.Expect(a.ctx).Return(make(map[string]*models.Order), nil)
+				f.repoMock.LoadAllOrdersMock.
+					Expect(a.ctx, models.LoadOptions{Limit: DefaultWarmupPageSize}).
+					Return(nil, nil)
 			},
 			wantCacheLen: 0,
-			wantErr:      false,
+			wantStatus:   models.WarmupStatus{State: models.WarmupReady},
 		},
 	}
 	for _, tt := range tests {
@@ -444,19 +514,75 @@ func Test_orderService_loadCache(t *testing.T) {
 
 			s := &orderService{
 				repo:  repoMock,
-				cache: make(map[string]*models.Order),
+				cache: newTestCache(),
 				log:   slog.Default(),
+				cfg:   tt.cfg,
 			}
 
 			tt.prepare(tt.args, &fields{
 				repoMock: repoMock,
 			})
 
-			s.loadCache(tt.args.ctx)
+			assert.Equal(t, models.WarmupPending, s.WarmupStatus().State)
+
+			s.WarmCache(tt.args.ctx)
 
-			s.mu.RLock()
-			assert.Len(t, s.cache, tt.wantCacheLen)
-			s.mu.RUnlock()
+			status := s.WarmupStatus()
+			assert.NotNil(t, status.StartedAt)
+			assert.NotNil(t, status.FinishedAt)
+			status.StartedAt, status.FinishedAt = nil, nil
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantCacheLen, s.cache.Len())
 		})
 	}
 }
+
+func Test_orderService_WarmCache_KeepsNewerEntries(t *testing.T) {
+	t.Parallel()
+
+	ctrl := minimock.NewController(t)
+	repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+
+	fresh := validOrder("uid1")
+	fresh.Version = 2
+
+	s := &orderService{
+		repo:  repoMock,
+		cache: newTestCache(),
+		log:   slog.Default(),
+	}
+	s.cache.Set(fresh)
+
+	repoMock.LoadAllOrdersMock.Return([]*models.Order{validOrder("uid1")}, nil)
+
+	s.WarmCache(context.Background())
+
+	got, ok := s.cache.Get("uid1")
+	assert.True(t, ok)
+	assert.Equal(t, 2, got.Version)
+}
+
+func Test_orderService_warmupOptions(t *testing.T) {
+	t.Parallel()
+
+	s := &orderService{cfg: config.CacheConfig{WarmupWindow: 24 * time.Hour, WarmupLimit: 500}}
+
+	opts := s.warmupOptions()
+	assert.False(t, opts.IncludeDeleted)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), opts.Since, time.Minute)
+
+	s = &orderService{}
+	assert.Equal(t, models.LoadOptions{}, s.warmupOptions())
+}
+
+func Test_orderService_warmupPageSize(t *testing.T) {
+	t.Parallel()
+
+	s := &orderService{cfg: config.CacheConfig{WarmupPageSize: 100, WarmupLimit: 250}}
+	assert.Equal(t, 100, s.warmupPageSize(0))
+	assert.Equal(t, 50, s.warmupPageSize(200))
+	assert.Equal(t, 0, s.warmupPageSize(250))
+
+	s = &orderService{}
+	assert.Equal(t, DefaultWarmupPageSize, s.warmupPageSize(1_000_000))
+}
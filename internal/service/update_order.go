@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/pkg/pgdb"
+)
+
+// UpdateOrder applies patch to the order if its current version equals
+// version. The updated order replaces the cached one once committed.
+func (s *orderService) UpdateOrder(
+	ctx context.Context,
+	uid string,
+	patch models.OrderPatch,
+	version int,
+) (*models.Order, error) {
+	if patch.Empty() {
+		return nil, ErrInvalidInput
+	}
+
+	var updated *models.Order
+
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		order, err := s.repo.GetOrderByUID(txCtx, uid, false)
+		if err != nil {
+			return err
+		}
+
+		if order.Version != version {
+			return ErrVersionConflict
+		}
+
+		patched := patch.Apply(order)
+		if err = s.validate(patched); err != nil {
+			return err
+		}
+
+		if err = s.repo.UpdateOrder(txCtx, patched, version); err != nil {
+			return err
+		}
+
+		patched.Version = version + 1
+		if err = s.recordEvents(txCtx, models.EventOrderUpdated, patched); err != nil {
+			return err
+		}
+
+		updated = patched
+		pgdb.AfterCommit(txCtx, func() { s.cacheOrder(patched) })
+
+		return nil
+	})
+	if err != nil {
+		return nil, mapDuplicate(err)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gojuno/minimock/v3"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"github.com/sdvaanyaa/order-service/internal/repository"
+	rmocks "github.com/sdvaanyaa/order-service/internal/repository/mocks"
+	tmocks "github.com/sdvaanyaa/order-service/pkg/pgdb/mocks"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func Test_orderService_UpdateOrder(t *testing.T) {
+	t.Parallel()
+
+	address := "new address"
+	badEmail := "not-an-email"
+
+	type fields struct {
+		repoMock       *rmocks.OrderRepositoryMock
+		outboxMock     *rmocks.OutboxRepositoryMock
+		transactorMock *tmocks.TransactorMock
+	}
+	type args struct {
+		ctx     context.Context
+		uid     string
+		patch   models.OrderPatch
+		version int
+	}
+	tests := []struct {
+		name    string
+		prepare func(a args, f *fields)
+		args    args
+		check   func(t *testing.T, got *models.Order)
+		wantErr error
+	}{
+		{
+			name: "Success",
+			args: args{
+				ctx: context.Background(),
+				uid: "uid1",
+				patch: models.OrderPatch{
+					Delivery:    &models.DeliveryPatch{Address: &address},
+					RemoveItems: []int64{1},
+					AddItems:    []models.Item{{ChrtID: 3, TrackNumber: "track1", Name: "new", Brand: "brand"}},
+				},
+				version: 1,
+			},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid, false).Return(validOrder(a.uid), nil)
+				f.repoMock.UpdateOrderMock.Set(func(_ context.Context, o *models.Order, version int) error {
+					assert.Equal(t, address, o.Delivery.Address)
+					assert.Equal(t, 1, version)
+					return nil
+				})
+				f.outboxMock.AddEventsMock.Set(func(_ context.Context, events []models.OutboxEvent) error {
+					assert.Len(t, events, 1)
+					assert.Equal(t, models.EventOrderUpdated, events[0].Type)
+
+					var event models.OrderEvent
+					assert.NoError(t, json.Unmarshal(events[0].Payload, &event))
+					assert.Equal(t, events[0].EventID, event.ID)
+					assert.Equal(t, 2, event.Version)
+					assert.Equal(t, address, event.Order.Delivery.Address)
+					return nil
+				})
+			},
+			check: func(t *testing.T, got *models.Order) {
+				assert.Equal(t, 2, got.Version)
+				assert.Equal(t, address, got.Delivery.Address)
+				assert.Len(t, got.Items, 1)
+				assert.Equal(t, int64(3), got.Items[0].ChrtID)
+			},
+		},
+		{
+			name:    "Empty Patch",
+			args:    args{ctx: context.Background(), uid: "uid1", version: 1},
+			prepare: func(a args, f *fields) {},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name: "Stale Version",
+			args: args{
+				ctx:     context.Background(),
+				uid:     "uid1",
+				patch:   models.OrderPatch{Delivery: &models.DeliveryPatch{Address: &address}},
+				version: 5,
+			},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid, false).Return(validOrder(a.uid), nil)
+			},
+			wantErr: ErrVersionConflict,
+		},
+		{
+			name: "Patched Order Invalid",
+			args: args{
+				ctx:     context.Background(),
+				uid:     "uid1",
+				patch:   models.OrderPatch{Delivery: &models.DeliveryPatch{Email: &badEmail}},
+				version: 1,
+			},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid, false).Return(validOrder(a.uid), nil)
+			},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name: "Concurrent Update",
+			args: args{
+				ctx:     context.Background(),
+				uid:     "uid1",
+				patch:   models.OrderPatch{Delivery: &models.DeliveryPatch{Address: &address}},
+				version: 1,
+			},
+			prepare: func(a args, f *fields) {
+				f.transactorMock.WithinTransactionMock.Set(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+				f.repoMock.GetOrderByUIDMock.Expect(a.ctx, a.uid, false).Return(validOrder(a.uid), nil)
+				f.repoMock.UpdateOrderMock.Return(repository.ErrVersionConflict)
+			},
+			wantErr: ErrVersionConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := minimock.NewController(t)
+			repoMock := rmocks.NewOrderRepositoryMock(ctrl)
+			outboxMock := rmocks.NewOutboxRepositoryMock(ctrl)
+			transactorMock := tmocks.NewTransactorMock(ctrl)
+
+			s := &orderService{
+				repo:       repoMock,
+				outbox:     outboxMock,
+				transactor: transactorMock,
+				log:        slog.Default(),
+				cache:      newTestCache(),
+				val:        validator.New(),
+			}
+
+			tt.prepare(tt.args, &fields{
+				repoMock:       repoMock,
+				outboxMock:     outboxMock,
+				transactorMock: transactorMock,
+			})
+
+			got, err := s.UpdateOrder(tt.args.ctx, tt.args.uid, tt.args.patch, tt.args.version)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			tt.check(t, got)
+
+			cached, _ := s.cache.Get(tt.args.uid)
+			assert.Equal(t, got, cached)
+		})
+	}
+}
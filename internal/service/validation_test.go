@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_newValidationError(t *testing.T) {
+	t.Parallel()
+
+	val := NewValidator()
+
+	order := validOrder("uid1")
+	order.Items = append(order.Items, order.Items[0], order.Items[0])
+	order.Items[2].Sale = 150
+	order.Delivery.Email = ""
+
+	err := newValidationError(val.Struct(order))
+
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	var verr *ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.Equal(t, []FieldError{
+		{Field: "delivery.email", Rule: "required", Message: "is required"},
+		{Field: "items[2].sale", Rule: "max", Message: "must be at most 100"},
+	}, verr.Fields)
+	assert.Equal(t, "invalid input: delivery.email: is required; items[2].sale: must be at most 100", err.Error())
+}
+
+func Test_newValidationError_Other(t *testing.T) {
+	t.Parallel()
+
+	err := newValidationError(errors.New("boom"))
+
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	var verr *ValidationError
+	assert.False(t, errors.As(err, &verr))
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"github.com/sdvaanyaa/order-service/internal/models"
+	"sync"
+	"time"
+)
+
+const DefaultWarmupPageSize = 1000
+
+// WarmCache loads the newest orders into the cache page by page. It is meant
+// to run in the background: until it is done GetOrder misses simply fall
+// through to the repository.
+func (s *orderService) WarmCache(ctx context.Context) {
+	if !s.warmup.start(time.Now(), false) {
+		s.log.Warn("cache warmup already running")
+		return
+	}
+
+	s.runWarmup(ctx)
+}
+
+func (s *orderService) runWarmup(ctx context.Context) {
+	s.log.Info("cache warmup started")
+
+	s.cache.BeginWarm()
+	defer s.cache.EndWarm()
+
+	loaded, err := s.warmPages(ctx)
+	if err != nil {
+		s.warmup.fail(time.Now(), err)
+		s.log.Error("cache warmup failed", "loaded", loaded, "err", err)
+		return
+	}
+
+	s.warmup.finish(time.Now())
+	s.log.Info("cache warmup finished", "loaded", loaded, "cached", s.cache.Len(), "duration", s.warmup.last())
+}
+
+func (s *orderService) WarmupStatus() models.WarmupStatus {
+	return s.warmup.status()
+}
+
+// warmPages walks the selected orders newest first and returns how many
+// were loaded.
+func (s *orderService) warmPages(ctx context.Context) (int, error) {
+	opts := s.warmupOptions()
+	loaded := 0
+
+	for {
+		opts.Limit = s.warmupPageSize(loaded)
+		if opts.Limit <= 0 {
+			return loaded, nil
+		}
+
+		gen := s.cache.Generation()
+		orders, err := s.repo.LoadAllOrders(ctx, opts)
+		if err != nil {
+			return loaded, err
+		}
+
+		// orders written or invalidated since gen may be newer than the
+		// page, so Warm leaves them to GetOrder
+		for _, order := range orders {
+			s.cache.Warm(order, gen)
+		}
+
+		loaded += len(orders)
+		s.warmup.progress(loaded)
+		s.log.Info("cache warmup progress", "loaded", loaded)
+
+		if len(orders) < opts.Limit {
+			return loaded, nil
+		}
+
+		last := orders[len(orders)-1]
+		opts.After = &models.OrderCursor{DateCreated: last.DateCreated, OrderUID: last.OrderUID}
+	}
+}
+
+// warmupOptions selects the newest orders allowed by the warmup window, if
+// configured.
+func (s *orderService) warmupOptions() models.LoadOptions {
+	var opts models.LoadOptions
+	if s.cfg.WarmupWindow > 0 {
+		opts.Since = time.Now().Add(-s.cfg.WarmupWindow)
+	}
+	return opts
+}
+
+// warmupPageSize caps the next page so the warmup limit is not exceeded.
+func (s *orderService) warmupPageSize(loaded int) int {
+	size := s.cfg.WarmupPageSize
+	if size <= 0 {
+		size = DefaultWarmupPageSize
+	}
+	if s.cfg.WarmupLimit > 0 {
+		size = min(size, s.cfg.WarmupLimit-loaded)
+	}
+	return size
+}
+
+type warmupTracker struct {
+	mu sync.RWMutex
+	st models.WarmupStatus
+
+	// duration of the last warmup that finished
+	lastDuration time.Duration
+}
+
+// start begins a new warmup unless one is running already. A reload warms
+// a cache that was ready before.
+func (t *warmupTracker) start(now time.Time, reload bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.st.State == models.WarmupRunning {
+		return false
+	}
+
+	t.st = models.WarmupStatus{State: models.WarmupRunning, Reload: reload, StartedAt: &now}
+	return true
+}
+
+func (t *warmupTracker) progress(loaded int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.st.Loaded = loaded
+}
+
+func (t *warmupTracker) finish(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.st.State = models.WarmupReady
+	t.st.FinishedAt = &now
+	t.lastDuration = now.Sub(*t.st.StartedAt)
+}
+
+func (t *warmupTracker) fail(now time.Time, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.st.State = models.WarmupFailed
+	t.st.FinishedAt = &now
+	t.st.Error = err.Error()
+}
+
+func (t *warmupTracker) status() models.WarmupStatus {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.st.State == "" {
+		return models.WarmupStatus{State: models.WarmupPending}
+	}
+	return t.st
+}
+
+func (t *warmupTracker) last() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lastDuration
+}
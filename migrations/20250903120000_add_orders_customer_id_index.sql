@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders (customer_id, date_created DESC, order_uid DESC);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_orders_customer_id;
+-- +goose StatementEnd
@@ -0,0 +1,81 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Manager shuts the registered components down one after another, in the
+// order they were added.
+type Manager struct {
+	log    *slog.Logger
+	stages []stage
+}
+
+type stage struct {
+	name    string
+	timeout time.Duration
+	stop    func(ctx context.Context) error
+}
+
+func New(log *slog.Logger) *Manager {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Manager{log: log}
+}
+
+// Add registers a shutdown stage. stop gets a context that ends after
+// timeout; zero means only the context passed to Shutdown applies.
+func (m *Manager) Add(name string, timeout time.Duration, stop func(ctx context.Context) error) {
+	m.stages = append(m.stages, stage{name: name, timeout: timeout, stop: stop})
+}
+
+// Shutdown runs every stage, also after one of them failed, so that later
+// resources are still released. The stage errors are joined.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	for _, s := range m.stages {
+		start := time.Now()
+		if err := s.run(ctx); err != nil {
+			m.log.Error("shutdown stage failed", slog.String("stage", s.name), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		m.log.Info("shutdown stage done", slog.String("stage", s.name), slog.Duration("took", time.Since(start)))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s stage) run(ctx context.Context) error {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	return s.stop(ctx)
+}
+
+// Wait waits for wg until ctx is done and returns the context error if the
+// group did not finish in time.
+func Wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
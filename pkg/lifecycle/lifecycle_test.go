@@ -0,0 +1,77 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+var ErrStage = errors.New("stage failed")
+
+func TestManager_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+	stop := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+
+	m := New(nil)
+	m.Add("http", 0, stop("http", nil))
+	m.Add("consumer", 0, stop("consumer", ErrStage))
+	m.Add("db", 0, stop("db", nil))
+
+	err := m.Shutdown(context.Background())
+
+	assert.Equal(t, []string{"http", "consumer", "db"}, order)
+	require.ErrorIs(t, err, ErrStage)
+	assert.Contains(t, err.Error(), "consumer: ")
+}
+
+func TestManager_Shutdown_StageTimeout(t *testing.T) {
+	t.Parallel()
+
+	m := New(nil)
+	m.Add("slow", time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var next context.Context
+	m.Add("next", 0, func(ctx context.Context) error {
+		next = ctx
+		return nil
+	})
+
+	err := m.Shutdown(context.Background())
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotNil(t, next)
+	assert.NoError(t, next.Err(), "the timeout of one stage does not cut the next")
+}
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	var wg sync.WaitGroup
+	release := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-release
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, Wait(ctx, &wg), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, Wait(context.Background(), &wg))
+}
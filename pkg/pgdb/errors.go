@@ -0,0 +1,15 @@
+package pgdb
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const uniqueViolationCode = "23505"
+
+// IsUniqueViolation reports whether err is a Postgres unique_violation.
+// Callers map it per statement, since each insert has one unique key.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
@@ -0,0 +1,45 @@
+package pgdb
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIsUniqueViolation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "Unique Violation",
+			err:  &pgconn.PgError{Code: "23505", ConstraintName: "orders_pkey"},
+			want: true,
+		},
+		{
+			name: "Wrapped Unique Violation",
+			err:  fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", ConstraintName: "payments_pkey"}),
+			want: true,
+		},
+		{
+			name: "Other Postgres Error",
+			err:  &pgconn.PgError{Code: "23503"},
+		},
+		{
+			name: "Plain Error",
+			err:  errors.New("boom"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, IsUniqueViolation(tt.err))
+		})
+	}
+}
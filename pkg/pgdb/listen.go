@@ -0,0 +1,79 @@
+package pgdb
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+	"time"
+)
+
+const (
+	listenMinBackoff = time.Second
+	listenMaxBackoff = 30 * time.Second
+)
+
+type Listener interface {
+	Listen(ctx context.Context, channel string, onNotify func(payload string), onReconnect func())
+}
+
+// Listen subscribes to channel on a dedicated connection and calls onNotify
+// for every notification until ctx is done. A dropped connection is
+// re-established with backoff. Notifications sent in the meantime are lost,
+// so onReconnect is called every time listening resumes after a drop.
+func (c *Client) Listen(ctx context.Context, channel string, onNotify func(payload string), onReconnect func()) {
+	backoff := listenMinBackoff
+	connected := false
+
+	for {
+		err := c.listen(ctx, channel, onNotify, func() {
+			if connected && onReconnect != nil {
+				onReconnect()
+			}
+			connected = true
+			backoff = listenMinBackoff
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.log.Error("listener connection lost",
+			slog.String("channel", channel),
+			slog.Duration("retry_in", backoff),
+			slog.Any("error", err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+}
+
+func (c *Client) listen(ctx context.Context, channel string, onNotify func(string), onConnect func()) error {
+	poolConn, err := c.conn.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the connection keeps listening, so it must not go back to the pool
+	conn := poolConn.Hijack()
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	c.log.Info("listening for notifications", slog.String("channel", channel))
+	onConnect()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		onNotify(n.Payload)
+	}
+}
@@ -2,6 +2,7 @@ package pgdb
 
 import (
 	"context"
+	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"log/slog"
@@ -49,6 +50,57 @@ func (c *Client) Exec(ctx context.Context, sql string, args ...any) (pgconn.Comm
 	return tag, err
 }
 
+// SendBatch sends all queued queries in one round trip. The caller must
+// close the returned results; the batch is logged once they are closed.
+func (c *Client) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	start := time.Now()
+
+	var results pgx.BatchResults
+	if tx := extractTx(ctx); tx != nil {
+		results = tx.SendBatch(ctx, b)
+	} else {
+		results = c.conn.SendBatch(ctx, b)
+	}
+
+	return &loggedBatch{BatchResults: results, client: c, start: start, size: b.Len()}
+}
+
+// loggedBatch logs the batch as a whole when it is closed, since the
+// results are only read by then.
+type loggedBatch struct {
+	pgx.BatchResults
+	client *Client
+	start  time.Time
+	size   int
+}
+
+func (b *loggedBatch) Close() error {
+	err := b.BatchResults.Close()
+	b.client.logQuery(fmt.Sprintf("BATCH of %d queries", b.size), time.Since(b.start), err)
+	return err
+}
+
+func (c *Client) CopyFrom(
+	ctx context.Context,
+	table pgx.Identifier,
+	columns []string,
+	src pgx.CopyFromSource,
+) (int64, error) {
+	start := time.Now()
+
+	var n int64
+	var err error
+
+	if tx := extractTx(ctx); tx != nil {
+		n, err = tx.CopyFrom(ctx, table, columns, src)
+	} else {
+		n, err = c.conn.CopyFrom(ctx, table, columns, src)
+	}
+
+	c.logQuery("COPY "+table.Sanitize(), time.Since(start), err)
+	return n, err
+}
+
 func (c *Client) logQuery(sql string, duration time.Duration, err error) {
 	fields := strings.Fields(sql)
 	operation := "UNKNOWN"
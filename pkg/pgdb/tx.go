@@ -34,7 +34,9 @@ func (t *Transaction) WithinTransaction(ctx context.Context, tFunc func(ctx cont
 
 	t.log.Debug("transaction began")
 
-	err = tFunc(injectTx(ctx, tx))
+	hooksCtx, hooks := WithHooks(ctx)
+
+	err = tFunc(injectTx(hooksCtx, tx))
 	if err != nil {
 		t.log.Error("transaction failed", slog.Any("error", err))
 		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
@@ -49,5 +51,7 @@ func (t *Transaction) WithinTransaction(ctx context.Context, tFunc func(ctx cont
 	}
 
 	t.log.Debug("transaction committed")
+	hooks.Run()
+
 	return nil
 }
@@ -3,10 +3,19 @@ package pgdb
 import (
 	"context"
 	"github.com/jackc/pgx/v5"
+	"sync"
 )
 
 type txKey struct{}
 
+type hooksKey struct{}
+
+// Hooks collects functions to run after a transaction commits
+type Hooks struct {
+	mu    sync.Mutex
+	funcs []func()
+}
+
 // injectTx injects transaction to context
 func injectTx(ctx context.Context, tx pgx.Tx) context.Context {
 	return context.WithValue(ctx, txKey{}, tx)
@@ -19,3 +28,37 @@ func extractTx(ctx context.Context) pgx.Tx {
 	}
 	return nil
 }
+
+// WithHooks returns a context that collects after-commit hooks. Transactor
+// implementations call it before running the transaction body and run the
+// hooks only if the commit succeeds.
+func WithHooks(ctx context.Context) (context.Context, *Hooks) {
+	hooks := &Hooks{}
+	return context.WithValue(ctx, hooksKey{}, hooks), hooks
+}
+
+// AfterCommit registers fn to run once the transaction in ctx is committed.
+// Outside a transaction fn runs immediately.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(hooksKey{}).(*Hooks)
+	if !ok {
+		fn()
+		return
+	}
+
+	hooks.mu.Lock()
+	hooks.funcs = append(hooks.funcs, fn)
+	hooks.mu.Unlock()
+}
+
+// Run calls the registered hooks in registration order
+func (h *Hooks) Run() {
+	h.mu.Lock()
+	funcs := h.funcs
+	h.funcs = nil
+	h.mu.Unlock()
+
+	for _, fn := range funcs {
+		fn()
+	}
+}
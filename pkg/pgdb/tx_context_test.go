@@ -0,0 +1,36 @@
+package pgdb
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAfterCommit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Outside Transaction Runs Immediately", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		AfterCommit(context.Background(), func() { called = true })
+		assert.True(t, called)
+	})
+
+	t.Run("Inside Transaction Deferred Until Run", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, hooks := WithHooks(context.Background())
+
+		var order []int
+		AfterCommit(ctx, func() { order = append(order, 1) })
+		AfterCommit(ctx, func() { order = append(order, 2) })
+		assert.Empty(t, order)
+
+		hooks.Run()
+		assert.Equal(t, []int{1, 2}, order)
+
+		hooks.Run()
+		assert.Equal(t, []int{1, 2}, order)
+	})
+}
@@ -0,0 +1,122 @@
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+type Jitter string
+
+const (
+	// JitterFull waits a random time between zero and the backoff.
+	JitterFull Jitter = "full"
+	// JitterEqual waits half the backoff plus a random part of the other half.
+	JitterEqual Jitter = "equal"
+	// JitterDecorrelated grows from the previous delay instead of the attempt
+	// number: a random time between base and three times the last delay.
+	JitterDecorrelated Jitter = "decorrelated"
+)
+
+// Valid reports whether j is one of the known jitter strategies. Next treats
+// anything else as full jitter.
+func (j Jitter) Valid() bool {
+	switch j {
+	case JitterFull, JitterEqual, JitterDecorrelated:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	DefaultFactor      = 2
+	decorrelatedFactor = 3
+)
+
+type Config struct {
+	Base   time.Duration
+	Max    time.Duration
+	Factor float64
+	Jitter Jitter
+}
+
+// Backoff hands out growing delays. It counts attempts until Reset, so one
+// Backoff can be kept across iterations of a long-running loop.
+type Backoff struct {
+	cfg     Config
+	attempt int
+	prev    time.Duration
+	rand    func() float64
+}
+
+func NewBackoff(cfg Config) *Backoff {
+	if cfg.Factor <= 1 {
+		cfg.Factor = DefaultFactor
+	}
+	if cfg.Jitter == "" {
+		cfg.Jitter = JitterFull
+	}
+
+	return &Backoff{
+		cfg:  cfg,
+		rand: rand.Float64,
+	}
+}
+
+// Next counts an attempt and returns how long to wait before the next one.
+func (b *Backoff) Next() time.Duration {
+	b.attempt++
+
+	if b.cfg.Jitter == JitterDecorrelated {
+		return b.decorrelated()
+	}
+
+	exp := b.exponential()
+	if b.cfg.Jitter == JitterEqual {
+		return exp/2 + b.random(exp/2)
+	}
+
+	return b.random(exp)
+}
+
+// Attempt returns how many delays were handed out since the last Reset.
+func (b *Backoff) Attempt() int {
+	return b.attempt
+}
+
+func (b *Backoff) Reset() {
+	b.attempt = 0
+	b.prev = 0
+}
+
+// exponential is base * factor^(attempt-1), capped at max.
+func (b *Backoff) exponential() time.Duration {
+	d := float64(b.cfg.Base) * math.Pow(b.cfg.Factor, float64(b.attempt-1))
+	return b.capped(d)
+}
+
+func (b *Backoff) decorrelated() time.Duration {
+	prev := max(b.prev, b.cfg.Base)
+	upper := b.capped(float64(prev) * decorrelatedFactor)
+
+	b.prev = b.cfg.Base + b.random(upper-b.cfg.Base)
+	return b.prev
+}
+
+func (b *Backoff) capped(d float64) time.Duration {
+	if b.cfg.Max > 0 && d > float64(b.cfg.Max) {
+		return b.cfg.Max
+	}
+	if d > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+func (b *Backoff) random(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(b.rand() * float64(d))
+}
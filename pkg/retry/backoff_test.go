@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		jitter Jitter
+		rand   float64
+		want   []time.Duration
+	}{
+		{
+			name:   "Full",
+			jitter: JitterFull,
+			rand:   0.5,
+			want:   []time.Duration{50, 100, 200, 400, 500, 500},
+		},
+		{
+			name:   "Equal",
+			jitter: JitterEqual,
+			rand:   0.5,
+			want:   []time.Duration{75, 150, 300, 600, 750, 750},
+		},
+		{
+			name:   "Decorrelated",
+			jitter: JitterDecorrelated,
+			rand:   0.5,
+			want:   []time.Duration{200, 350, 550, 550, 550, 550},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := NewBackoff(Config{
+				Base:   100 * time.Nanosecond,
+				Max:    1000 * time.Nanosecond,
+				Jitter: tt.jitter,
+			})
+			b.rand = func() float64 { return tt.rand }
+
+			got := make([]time.Duration, 0, len(tt.want))
+			for range tt.want {
+				got = append(got, b.Next())
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, len(tt.want), b.Attempt())
+		})
+	}
+}
+
+func TestBackoff_Reset(t *testing.T) {
+	t.Parallel()
+
+	b := NewBackoff(Config{Base: time.Second, Max: time.Minute, Jitter: JitterEqual})
+	b.rand = func() float64 { return 0 }
+
+	b.Next()
+	b.Next()
+	assert.Equal(t, 4*time.Second/2, b.Next())
+
+	b.Reset()
+	assert.Equal(t, 0, b.Attempt())
+	assert.Equal(t, time.Second/2, b.Next())
+}
+
+func TestBackoff_Bounds(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Base: time.Millisecond, Max: 50 * time.Millisecond}
+	for _, jitter := range []Jitter{JitterFull, JitterEqual, JitterDecorrelated} {
+		cfg.Jitter = jitter
+		b := NewBackoff(cfg)
+		for range 100 {
+			d := b.Next()
+			assert.GreaterOrEqual(t, d, time.Duration(0), jitter)
+			assert.LessOrEqual(t, d, cfg.Max, jitter)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+type Policy struct {
+	Backoff Config
+	// MaxAttempts limits the calls of fn. Zero means no limit.
+	MaxAttempts int
+	// AttemptTimeout bounds every single call. Zero means no timeout.
+	AttemptTimeout time.Duration
+	// Retryable reports whether an error is worth another attempt. Nil
+	// retries every error.
+	Retryable func(err error) bool
+	// OnRetry, if set, is called before waiting for the next attempt.
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// Do calls fn until it succeeds, fails with an error that is not retryable,
+// runs out of attempts or ctx is done. It returns the number of calls made
+// and the last error.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error) (int, error) {
+	backoff := NewBackoff(p.Backoff)
+
+	for attempt := 1; ; attempt++ {
+		err := p.call(ctx, fn)
+		if err == nil {
+			return attempt, nil
+		}
+
+		if ctx.Err() != nil || !p.retryable(err) || (p.MaxAttempts > 0 && attempt >= p.MaxAttempts) {
+			return attempt, err
+		}
+
+		delay := backoff.Next()
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, delay)
+		}
+
+		if Wait(ctx, delay) != nil {
+			return attempt, err
+		}
+	}
+}
+
+func (p Policy) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.AttemptTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.AttemptTimeout)
+	defer cancel()
+
+	return fn(ctx)
+}
+
+func (p Policy) retryable(err error) bool {
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// Wait sleeps for d, or returns ctx.Err() as soon as ctx is done.
+func Wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var (
+	ErrTransient = errors.New("transient")
+	ErrPermanent = errors.New("permanent")
+)
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	fast := Config{Base: time.Microsecond, Max: time.Millisecond}
+
+	tests := []struct {
+		name         string
+		policy       Policy
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "Success",
+			policy:       Policy{Backoff: fast, MaxAttempts: 3},
+			wantAttempts: 1,
+		},
+		{
+			name:         "Retried Until Success",
+			policy:       Policy{Backoff: fast, MaxAttempts: 3},
+			errs:         []error{ErrTransient, ErrTransient},
+			wantAttempts: 3,
+		},
+		{
+			name:         "Out Of Attempts",
+			policy:       Policy{Backoff: fast, MaxAttempts: 2},
+			errs:         []error{ErrTransient, ErrTransient, ErrTransient},
+			wantAttempts: 2,
+			wantErr:      ErrTransient,
+		},
+		{
+			name: "Not Retryable",
+			policy: Policy{
+				Backoff:     fast,
+				MaxAttempts: 3,
+				Retryable:   func(err error) bool { return !errors.Is(err, ErrPermanent) },
+			},
+			errs:         []error{ErrTransient, ErrPermanent, ErrTransient},
+			wantAttempts: 2,
+			wantErr:      ErrPermanent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			errs := tt.errs
+			var retries []int
+			tt.policy.OnRetry = func(attempt int, _ error, _ time.Duration) {
+				retries = append(retries, attempt)
+			}
+
+			attempts, err := Do(context.Background(), tt.policy, func(context.Context) error {
+				if len(errs) == 0 {
+					return nil
+				}
+				err := errs[0]
+				errs = errs[1:]
+				return err
+			})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantAttempts, attempts)
+			assert.Len(t, retries, tt.wantAttempts-1)
+		})
+	}
+}
+
+func TestDo_CancelDuringWait(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := Policy{
+		Backoff: Config{Base: time.Hour, Max: time.Hour, Jitter: JitterEqual},
+		OnRetry: func(int, error, time.Duration) { cancel() },
+	}
+
+	start := time.Now()
+	attempts, err := Do(ctx, policy, func(context.Context) error { return ErrTransient })
+
+	assert.ErrorIs(t, err, ErrTransient)
+	assert.Equal(t, 1, attempts)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestDo_AttemptTimeout(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{
+		Backoff:        Config{Base: time.Microsecond},
+		MaxAttempts:    2,
+		AttemptTimeout: 10 * time.Millisecond,
+	}
+
+	attempts, err := Do(context.Background(), policy, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, Wait(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, Wait(ctx, time.Hour), context.Canceled)
+	assert.ErrorIs(t, Wait(ctx, 0), context.Canceled)
+}